@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/got/got"
+)
+
+var domain, token string
+
+// challengeArgs returns the challenge record name and token to work on,
+// from flags or positional arguments as passed by lego's exec provider.
+func challengeArgs(args []string) (string, string) {
+	if len(args) == 2 {
+		return got.ChallengeName(args[0]), args[1]
+	}
+	if len(domain) <= 0 {
+		log.Fatal("No domain specified")
+	}
+	if len(token) <= 0 {
+		log.Fatal("No token specified")
+	}
+	return got.ChallengeName(domain), token
+}
+
+// challengeProvider returns the provider for the zone the challenge record
+// goes to, either the one selected or the closest hosted zone to name.
+func challengeProvider(name string) got.Provider {
+	if len(zoneName) > 0 || len(zoneID) > 0 || providerName != "route53" {
+		return selectProvider()
+	}
+	svc := got.Init()
+	zone, err := got.FindZoneForName(name, privateFlag(), svc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	return got.NewRoute53Provider(zone, svc)
+}
+
+// applyChallenge submits changes for the challenge, waiting for them to be
+// in sync if required.
+func applyChallenge(
+	provider got.Provider,
+	changes []*route53.Change,
+	sync bool,
+) {
+	if len(changes) <= 0 {
+		log.Println("Nothing to change")
+		return
+	}
+	applyChanges(provider, changes, sync)
+}
+
+// acmeCmd represents the acme command
+var acmeCmd = &cobra.Command{
+	Use:   "acme",
+	Short: "ACME DNS-01 challenge hooks",
+	Long: `Create and remove ACME DNS-01 challenge TXT records, to be used as
+certbot manual hooks or lego exec provider.
+
+Domain and token default to CERTBOT_DOMAIN and CERTBOT_VALIDATION
+environment variables, and can be passed as positional arguments too,
+as lego does. Exits with status 0 on success, and non zero otherwise.`,
+}
+
+// acmePresentCmd represents the acme present command
+var acmePresentCmd = &cobra.Command{
+	Use:   "present [flags] [<fqdn> <value>]",
+	Short: "Create the challenge record",
+	Long: `Add the token to the _acme-challenge TXT record of the domain, and
+wait for the change to be in sync.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, value := challengeArgs(args)
+		provider := challengeProvider(name)
+		list := listRecords(provider)
+		changes := got.PresentChallengeChangeList(list, name, value)
+		applyChallenge(provider, changes, true)
+	},
+}
+
+// acmeCleanupCmd represents the acme cleanup command
+var acmeCleanupCmd = &cobra.Command{
+	Use:   "cleanup [flags] [<fqdn> <value>]",
+	Short: "Remove the challenge record",
+	Long: `Remove the token from the _acme-challenge TXT record of the domain,
+deleting the record if no other tokens are left.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, value := challengeArgs(args)
+		provider := challengeProvider(name)
+		list := listRecords(provider)
+		changes := got.CleanupChallengeChangeList(list, name, value)
+		applyChallenge(provider, changes, wait)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(acmeCmd)
+	acmeCmd.AddCommand(acmePresentCmd)
+	acmeCmd.AddCommand(acmeCleanupCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// acmeCmd.PersistentFlags().String("foo", "", "A help for foo")
+	acmeCmd.PersistentFlags().StringVarP(
+		&domain,
+		"domain",
+		"",
+		os.Getenv("CERTBOT_DOMAIN"),
+		"Domain being validated.",
+	)
+	acmeCmd.PersistentFlags().StringVarP(
+		&token,
+		"token",
+		"",
+		os.Getenv("CERTBOT_VALIDATION"),
+		"Validation token.",
+	)
+	acmeCmd.PersistentFlags().BoolVarP(
+		&dryrun,
+		"dryrun",
+		"",
+		false,
+		"Don't really do anything",
+	)
+	acmeCmd.PersistentFlags().BoolVarP(
+		&wait,
+		"wait",
+		"",
+		false,
+		"Don't return until cleanup is completed",
+	)
+	acmeCmd.PersistentFlags().BoolVarP(
+		&verify,
+		"verify",
+		"",
+		false,
+		"Wait until all zone nameservers answer the change",
+	)
+	acmeCmd.PersistentFlags().DurationVarP(
+		&verifyTimeout,
+		"verify-timeout",
+		"",
+		5*time.Minute,
+		"Time to wait for nameservers to answer the change",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// acmeCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
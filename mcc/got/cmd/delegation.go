@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/got/got"
+)
+
+var rootServers []string
+
+// parentRecords returns the record sets of the parent hosted zone of the
+// zone named apex, if it's in Route53 too.
+func parentRecords(
+	apex string,
+	provider got.Provider,
+) (list []*route53.ResourceRecordSet, ok bool) {
+	r53, ok := provider.(*got.Route53Provider)
+	if !ok || got.ParentName(apex) == "" {
+		return nil, false
+	}
+	parent, err := got.FindZoneForName(
+		got.ParentName(apex),
+		privateFlag(),
+		r53.Svc,
+	)
+	if err != nil {
+		return nil, false
+	}
+	return got.GetResourceRecordSet(*parent.Id, r53.Svc), true
+}
+
+// parentDelegation returns the nameservers the parent of the zone named
+// apex delegates it to, reading the parent hosted zone if there's one,
+// and following referrals from the root servers otherwise.
+func parentDelegation(
+	apex string,
+	provider got.Provider,
+	resolver got.Resolver,
+) []string {
+	if list, ok := parentRecords(apex, provider); ok {
+		return got.RecordValues(list, apex, route53.RRTypeNs)
+	}
+	delegated, err := got.LookupDelegation(apex, rootServers, resolver)
+	if err != nil {
+		log.Println(err.Error())
+	}
+	return delegated
+}
+
+// childDelegation returns the problems of a subdomain delegation, reading
+// the child hosted zone if there's one, and asking the nameservers it's
+// delegated to otherwise.
+func childDelegation(
+	set *route53.ResourceRecordSet,
+	provider got.Provider,
+	resolver got.Resolver,
+) []string {
+	delegated := []string{}
+	for _, record := range set.ResourceRecords {
+		delegated = append(delegated, *record.Value)
+	}
+	if r53, ok := provider.(*got.Route53Provider); ok {
+		child, err := got.FindHostedZone(
+			got.ZoneSelector{Name: *set.Name, Private: privateFlag()},
+			r53.Svc,
+		)
+		if err == nil {
+			return got.CheckDelegation(
+				*set.Name,
+				got.GetResourceRecordSet(*child.Id, r53.Svc),
+				delegated,
+				resolver,
+			)
+		}
+	}
+	return got.CheckNameServers(*set.Name, "", delegated, resolver)
+}
+
+// delegationCmd represents the delegation command
+var delegationCmd = &cobra.Command{
+	Use:   "delegation [flags]",
+	Short: "Check the delegations of a DNS zone",
+	Long: `Compare the NS record of a zone with the nameservers its parent
+delegates it to, checking all of them answer the zone SOA record with the
+same serial. Subdomains delegated from the zone are checked against their
+child hosted zones the same way, or just for lame nameservers if there's
+none. Parent zones not hosted in Route53 are looked up following
+referrals from the root servers.
+Exits with status 1 when problems are found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		provider := selectProvider()
+		apex := provider.Zone()
+		list := listRecords(provider)
+		resolver := got.NewDNSResolver()
+		problems := got.CheckDelegation(
+			apex,
+			list,
+			parentDelegation(apex, provider, resolver),
+			resolver,
+		)
+		for _, set := range got.SubdomainDelegations(list, apex) {
+			problems = append(
+				problems,
+				childDelegation(set, provider, resolver)...,
+			)
+		}
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(delegationCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// delegationCmd.PersistentFlags().String("foo", "", "A help for foo")
+	delegationCmd.PersistentFlags().StringSliceVarP(
+		&rootServers,
+		"root-servers",
+		"",
+		got.RootServers,
+		"Nameservers to look up delegations from.",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// delegationCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
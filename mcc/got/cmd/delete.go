@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"log"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/spf13/cobra"
 
 	"github.com/poka-yoke/spaceflight/mcc/got/got"
@@ -14,26 +17,31 @@ var deleteCmd = &cobra.Command{
 	Short: "Remove DNS records",
 	Long:  ``,
 	Run: func(cmd *cobra.Command, args []string) {
-		svc := got.Init()
-		if len(zoneName) <= 0 {
-			log.Fatal("No zone name specified")
-		}
 		if len(typ) <= 0 {
 			log.Fatal("No record type specified")
 		}
 		if len(args) <= 0 {
 			log.Fatal("No record names specified")
 		}
-		zoneid := got.GetZoneID(zoneName, svc)
-		list := got.GetResourceRecordSet(zoneid, svc)
-		changes := got.DeleteChangeList(args, typ, list)
-		if !dryrun {
-			res, err := got.ApplyChanges(changes, &zoneid, svc)
-			if err != nil {
-				log.Fatal(err.Error())
-			}
-			log.Println(res)
+		provider := selectProvider()
+		list := listRecords(provider)
+		if len(setID) > 0 {
+			list = got.FilterResourceRecords(
+				list,
+				[]string{setID},
+				func(
+					elem *route53.ResourceRecordSet,
+					filter string,
+				) *route53.ResourceRecordSet {
+					if aws.StringValue(elem.SetIdentifier) == filter {
+						return elem
+					}
+					return nil
+				},
+			)
 		}
+		changes := got.DeleteChangeList(args, typ, list)
+		applyChanges(provider, changes, wait)
 	},
 }
 
@@ -59,12 +67,19 @@ func init() {
 		false,
 		"Don't return until operation is completed",
 	)
-	deleteCmd.PersistentFlags().StringVarP(
-		&zoneName,
-		"zone",
+	deleteCmd.PersistentFlags().BoolVarP(
+		&verify,
+		"verify",
 		"",
+		false,
+		"Wait until all zone nameservers answer the change",
+	)
+	deleteCmd.PersistentFlags().DurationVarP(
+		&verifyTimeout,
+		"verify-timeout",
 		"",
-		"Name of the zone to work on.",
+		5*time.Minute,
+		"Time to wait for nameservers to answer the change",
 	)
 	deleteCmd.PersistentFlags().StringVarP(
 		&typ,
@@ -73,6 +88,13 @@ func init() {
 		"",
 		"Type of the record to upsert.",
 	)
+	deleteCmd.PersistentFlags().StringVarP(
+		&setID,
+		"set-id",
+		"",
+		"",
+		"Set identifier of weighted or failover records.",
+	)
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/got/got"
+)
+
+var against, againstID, againstFile string
+var all, againstPrivate bool
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff [flags]",
+	Short: "Compare a DNS zone with another zone or a snapshot",
+	Long: `Compare the record sets of a DNS zone with the ones of another zone,
+or with a snapshot exported by got export. Names are compared relative to
+each zone apex, so differently named zones can be compared.
+Exits with status 1 when differences are found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(against) <= 0 && len(againstID) <= 0 && len(againstFile) <= 0 {
+			log.Fatal("No zone or snapshot to compare with specified")
+		}
+		provider := selectProvider()
+		list := listRecords(provider)
+		var otherList []*route53.ResourceRecordSet
+		var otherApex string
+		if len(againstFile) > 0 {
+			f, err := os.Open(againstFile)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			defer f.Close()
+			otherList, err = got.ReadSnapshot(f)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			otherApex, err = got.ApexName(otherList)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+		} else {
+			selector := got.ZoneSelector{Name: against, ID: againstID}
+			if cmd.Flags().Changed("against-private") {
+				selector.Private = &againstPrivate
+			}
+			other := providerFor(selector)
+			otherList = listRecords(other)
+			otherApex = other.Zone()
+		}
+		diff := got.DiffRecordSets(otherList, otherApex, list, provider.Zone(), all)
+		fmt.Print(diff)
+		if !diff.Empty() {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(diffCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// diffCmd.PersistentFlags().String("foo", "", "A help for foo")
+	diffCmd.PersistentFlags().StringVarP(
+		&against,
+		"against",
+		"",
+		"",
+		"Name of the zone to compare with.",
+	)
+	diffCmd.PersistentFlags().StringVarP(
+		&againstID,
+		"against-id",
+		"",
+		"",
+		"ID of the zone to compare with.",
+	)
+	diffCmd.PersistentFlags().BoolVarP(
+		&againstPrivate,
+		"against-private",
+		"",
+		false,
+		"Whether the zone to compare with is private.",
+	)
+	diffCmd.PersistentFlags().StringVarP(
+		&againstFile,
+		"against-file",
+		"",
+		"",
+		"Snapshot file to compare with.",
+	)
+	diffCmd.PersistentFlags().BoolVarP(
+		&all,
+		"all",
+		"",
+		false,
+		"Compare apex SOA and NS records too",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// diffCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
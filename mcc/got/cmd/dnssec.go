@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/got/got"
+)
+
+var kmsKeyARN, kskName string
+var deleteKeys bool
+
+// route53Provider returns the provider of the selected zone, exiting
+// unless it's a Route53 hosted zone.
+func route53Provider() *got.Route53Provider {
+	provider, ok := selectProvider().(*got.Route53Provider)
+	if !ok {
+		log.Fatal("DNSSEC signing can only be managed for Route53 zones")
+	}
+	return provider
+}
+
+// dnssecCmd represents the dnssec command
+var dnssecCmd = &cobra.Command{
+	Use:   "dnssec",
+	Short: "Manage DNSSEC signing of DNS zones",
+	Long:  ``,
+}
+
+// dnssecStatusCmd represents the dnssec status command
+var dnssecStatusCmd = &cobra.Command{
+	Use:   "status [flags]",
+	Short: "Show DNSSEC signing status of a DNS zone",
+	Long: `Show whether the zone is signed, and name, status, key tag and DS
+record of its key signing keys.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		provider := route53Provider()
+		status, err := got.GetDNSSEC(provider.ZoneID, provider.Svc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		fmt.Print(got.FormatDNSSEC(status))
+	},
+}
+
+// dnssecEnableCmd represents the dnssec enable command
+var dnssecEnableCmd = &cobra.Command{
+	Use:   "enable [flags]",
+	Short: "Enable DNSSEC signing of a DNS zone",
+	Long: `Create a key signing key backed by a KMS key, unless the zone has
+one with the same name already, and enable DNSSEC signing. The DS record
+to add to the parent zone is printed afterwards.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(kmsKeyARN) <= 0 {
+			log.Fatal("No KMS key ARN specified")
+		}
+		provider := route53Provider()
+		if dryrun {
+			return
+		}
+		changeInfo, err := got.EnableDNSSEC(
+			provider.ZoneID,
+			kskName,
+			kmsKeyARN,
+			provider.Svc,
+		)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		log.Println(changeInfo)
+		if wait {
+			got.WaitForChangeToComplete(changeInfo, provider.Svc)
+		}
+		status, err := got.GetDNSSEC(provider.ZoneID, provider.Svc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, ds := range got.DSRecords(status) {
+			fmt.Printf("%s DS %s\n", provider.Zone(), ds)
+		}
+	},
+}
+
+// dnssecDisableCmd represents the dnssec disable command
+var dnssecDisableCmd = &cobra.Command{
+	Use:   "disable [flags]",
+	Short: "Disable DNSSEC signing of a DNS zone",
+	Long: `Disable DNSSEC signing of the zone. Its key signing keys are kept, so
+enabling it again uses the same DS record, unless --delete-keys is used,
+which deactivates and deletes them and can't be undone.
+The DS record must be removed from the parent zone first, or the zone will
+fail to resolve for validating resolvers, so disabling is refused while
+the parent publishes it, or can't be checked, unless --force is used.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		provider := route53Provider()
+		published, err := parentDS(
+			provider.Zone(),
+			provider,
+			got.NewDNSResolver(),
+		)
+		if err != nil {
+			if !force {
+				log.Fatalf(
+					"Can't check the parent zone DS records for %s, "+
+						"use --force to disable anyway: %s",
+					provider.Zone(),
+					err,
+				)
+			}
+			log.Println(err.Error())
+		}
+		if len(published) > 0 && !force {
+			log.Fatalf(
+				"Parent zone still publishes DS records for %s, "+
+					"remove them first or use --force",
+				provider.Zone(),
+			)
+		}
+		if dryrun {
+			return
+		}
+		changeInfo, err := got.DisableDNSSEC(
+			provider.ZoneID,
+			deleteKeys,
+			provider.Svc,
+		)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		log.Println(changeInfo)
+		if wait {
+			got.WaitForChangeToComplete(changeInfo, provider.Svc)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(dnssecCmd)
+	dnssecCmd.AddCommand(dnssecStatusCmd)
+	dnssecCmd.AddCommand(dnssecEnableCmd)
+	dnssecCmd.AddCommand(dnssecDisableCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// dnssecCmd.PersistentFlags().String("foo", "", "A help for foo")
+	dnssecCmd.PersistentFlags().BoolVarP(
+		&dryrun,
+		"dryrun",
+		"",
+		false,
+		"Don't really do anything",
+	)
+	dnssecCmd.PersistentFlags().BoolVarP(
+		&wait,
+		"wait",
+		"",
+		false,
+		"Don't return until operation is completed",
+	)
+	dnssecEnableCmd.PersistentFlags().StringVarP(
+		&kmsKeyARN,
+		"kms-key-arn",
+		"",
+		"",
+		"ARN of the asymmetric ECC_NIST_P256 KMS key in us-east-1 to sign with.",
+	)
+	dnssecEnableCmd.PersistentFlags().StringVarP(
+		&kskName,
+		"ksk-name",
+		"",
+		"got",
+		"Name of the key signing key.",
+	)
+	dnssecDisableCmd.PersistentFlags().BoolVarP(
+		&force,
+		"force",
+		"",
+		false,
+		"Disable even if the parent zone publishes DS records",
+	)
+	dnssecDisableCmd.PersistentFlags().BoolVarP(
+		&deleteKeys,
+		"delete-keys",
+		"",
+		false,
+		"Deactivate and delete the key signing keys too. Can't be undone.",
+	)
+	dnssecDisableCmd.PersistentFlags().StringSliceVarP(
+		&rootServers,
+		"root-servers",
+		"",
+		got.RootServers,
+		"Nameservers to look up parent zones from.",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// dnssecCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/got/got"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [flags]",
+	Short: "Export a DNS zone snapshot",
+	Long: `Print all record sets of a DNS zone in JSON, in the same format
+aws route53 list-resource-record-sets does, to be compared by got diff.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		list := listRecords(selectProvider())
+		if err := got.WriteSnapshot(os.Stdout, list); err != nil {
+			log.Fatal(err.Error())
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(exportCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// exportCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// exportCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
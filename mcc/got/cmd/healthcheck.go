@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/got/got"
+)
+
+var healthCheck got.HealthCheckParams
+
+// healthCheckCmd represents the healthcheck command
+var healthCheckCmd = &cobra.Command{
+	Use:   "healthcheck",
+	Short: "Manage health checks",
+	Long: `Manage HTTP, HTTPS and TCP health checks, to be attached to record
+sets with got upsert --health-check.`,
+}
+
+// healthCheckListCmd represents the healthcheck list command
+var healthCheckListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List health checks",
+	Long: `List ID, type, endpoint, interval and failure threshold of all
+health checks.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		svc := got.Init()
+		checks, err := got.ListHealthChecks(svc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		fmt.Print(got.FormatHealthChecks(checks))
+	},
+}
+
+// healthCheckCreateCmd represents the healthcheck create command
+var healthCheckCreateCmd = &cobra.Command{
+	Use:   "create [flags]",
+	Short: "Create a health check",
+	Long:  `Create a health check and print its ID.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		svc := got.Init()
+		if len(healthCheck.FQDN) <= 0 && len(healthCheck.IPAddress) <= 0 {
+			log.Fatal("No FQDN or IP address specified")
+		}
+		id, err := got.CreateHealthCheck(healthCheck, svc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		fmt.Println(id)
+	},
+}
+
+// healthCheckUpdateCmd represents the healthcheck update command
+var healthCheckUpdateCmd = &cobra.Command{
+	Use:   "update [flags] <id>",
+	Short: "Update a health check",
+	Long: `Update endpoint, path or failure threshold of a health check. Type
+and interval can't be updated.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		svc := got.Init()
+		if len(args) != 1 {
+			log.Fatal("You must specify a health check ID")
+		}
+		if err := got.UpdateHealthCheck(args[0], healthCheck, svc); err != nil {
+			log.Fatal(err.Error())
+		}
+	},
+}
+
+// healthCheckDeleteCmd represents the healthcheck delete command
+var healthCheckDeleteCmd = &cobra.Command{
+	Use:   "delete <id> ...",
+	Short: "Delete health checks",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		svc := got.Init()
+		if len(args) <= 0 {
+			log.Fatal("You must specify a health check ID")
+		}
+		for _, id := range args {
+			if err := got.DeleteHealthCheck(id, svc); err != nil {
+				log.Fatal(err.Error())
+			}
+			log.Printf("Deleted health check %s\n", id)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(healthCheckCmd)
+	healthCheckCmd.AddCommand(healthCheckListCmd)
+	healthCheckCmd.AddCommand(healthCheckCreateCmd)
+	healthCheckCmd.AddCommand(healthCheckUpdateCmd)
+	healthCheckCmd.AddCommand(healthCheckDeleteCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// healthCheckCmd.PersistentFlags().String("foo", "", "A help for foo")
+	healthCheckCreateCmd.PersistentFlags().StringVarP(
+		&healthCheck.Type,
+		"type",
+		"",
+		"HTTP",
+		"Type of the health check: HTTP, HTTPS or TCP.",
+	)
+	healthCheckCreateCmd.PersistentFlags().Int64VarP(
+		&healthCheck.Interval,
+		"interval",
+		"",
+		30,
+		"Seconds in between checks: 10 or 30.",
+	)
+	healthCheckCreateCmd.PersistentFlags().StringVarP(
+		&healthCheck.FQDN,
+		"fqdn",
+		"",
+		"",
+		"Domain name of the endpoint to check.",
+	)
+	healthCheckCreateCmd.PersistentFlags().StringVarP(
+		&healthCheck.IPAddress,
+		"ip",
+		"",
+		"",
+		"IP address of the endpoint to check.",
+	)
+	healthCheckCreateCmd.PersistentFlags().Int64VarP(
+		&healthCheck.Port,
+		"port",
+		"",
+		0,
+		"Port of the endpoint to check.",
+	)
+	healthCheckCreateCmd.PersistentFlags().StringVarP(
+		&healthCheck.Path,
+		"path",
+		"",
+		"",
+		"Path to request for HTTP and HTTPS checks.",
+	)
+	healthCheckCreateCmd.PersistentFlags().Int64VarP(
+		&healthCheck.FailureThreshold,
+		"failure-threshold",
+		"",
+		0,
+		"Consecutive failures to consider the endpoint unhealthy.",
+	)
+	healthCheckUpdateCmd.PersistentFlags().StringVarP(
+		&healthCheck.FQDN,
+		"fqdn",
+		"",
+		"",
+		"Domain name of the endpoint to check.",
+	)
+	healthCheckUpdateCmd.PersistentFlags().StringVarP(
+		&healthCheck.IPAddress,
+		"ip",
+		"",
+		"",
+		"IP address of the endpoint to check.",
+	)
+	healthCheckUpdateCmd.PersistentFlags().Int64VarP(
+		&healthCheck.Port,
+		"port",
+		"",
+		0,
+		"Port of the endpoint to check.",
+	)
+	healthCheckUpdateCmd.PersistentFlags().StringVarP(
+		&healthCheck.Path,
+		"path",
+		"",
+		"",
+		"Path to request for HTTP and HTTPS checks.",
+	)
+	healthCheckUpdateCmd.PersistentFlags().Int64VarP(
+		&healthCheck.FailureThreshold,
+		"failure-threshold",
+		"",
+		0,
+		"Consecutive failures to consider the endpoint unhealthy.",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// healthCheckCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
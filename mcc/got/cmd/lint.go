@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/got/got"
+)
+
+// parentDS returns the DS records the parent of the zone named apex
+// publishes, reading the parent hosted zone if there's one, and asking
+// its nameservers otherwise.
+func parentDS(
+	apex string,
+	provider got.Provider,
+	resolver got.Resolver,
+) ([]string, error) {
+	if list, ok := parentRecords(apex, provider); ok {
+		return got.RecordValues(list, apex, route53.RRTypeDs), nil
+	}
+	return got.LookupDS(apex, rootServers, resolver)
+}
+
+// zoneDS returns the DS records matching the key signing keys of the zone,
+// from Route53 signing configuration or from the zone DNSKEY record.
+func zoneDS(
+	provider got.Provider,
+	list []*route53.ResourceRecordSet,
+) []string {
+	if r53, ok := provider.(*got.Route53Provider); ok {
+		status, err := got.GetDNSSEC(r53.ZoneID, r53.Svc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		return got.DSRecords(status)
+	}
+	return got.KeyDSRecords(list, provider.Zone())
+}
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint [flags]",
+	Short: "Check a DNS zone for common mistakes",
+	Long: `Check a DNS zone for mistakes, printing a warning for each of them:
+- DS records published by the parent zone not matching the zone key
+  signing keys, or missing.
+Exits with status 1 when warnings are printed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		provider := selectProvider()
+		apex := provider.Zone()
+		list := listRecords(provider)
+		published, err := parentDS(apex, provider, got.NewDNSResolver())
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		warnings := got.CheckDS(apex, zoneDS(provider, list), published)
+		for _, warning := range warnings {
+			fmt.Println(warning)
+		}
+		if len(warnings) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(lintCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// lintCmd.PersistentFlags().String("foo", "", "A help for foo")
+	lintCmd.PersistentFlags().StringSliceVarP(
+		&rootServers,
+		"root-servers",
+		"",
+		got.RootServers,
+		"Nameservers to look up parent zones from.",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// lintCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+
+	"github.com/poka-yoke/spaceflight/mcc/got/got"
+)
+
+var providerName, server, tsigName, tsigSecret, tsigAlgorithm string
+
+// providerFor returns the provider for the zone described by selector,
+// using the backend selected through the global provider flags.
+func providerFor(selector got.ZoneSelector) got.Provider {
+	switch providerName {
+	case "route53":
+		svc := got.Init()
+		zone, err := got.FindHostedZone(selector, svc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		return got.NewRoute53Provider(zone, svc)
+	case "rfc2136":
+		if len(server) <= 0 {
+			log.Fatal("No server specified")
+		}
+		if len(selector.Name) <= 0 {
+			log.Fatal("No zone name specified")
+		}
+		var key *got.TSIGKey
+		if len(tsigSecret) <= 0 {
+			tsigSecret = os.Getenv("GOT_TSIG_SECRET")
+		}
+		if len(tsigName) > 0 {
+			key = &got.TSIGKey{
+				Name:      tsigName,
+				Secret:    tsigSecret,
+				Algorithm: tsigAlgorithm,
+			}
+		}
+		return got.NewRFC2136Provider(server, selector.Name, key)
+	}
+	log.Fatalf("Unknown provider %s", providerName)
+	return nil
+}
+
+// selectProvider returns the provider for the zone selected through the
+// global zone flags.
+func selectProvider() got.Provider {
+	return providerFor(got.ZoneSelector{
+		Name:    zoneName,
+		ID:      zoneID,
+		Private: privateFlag(),
+	})
+}
+
+// listRecords returns all record sets in the zone of provider, exiting on
+// errors.
+func listRecords(provider got.Provider) []*route53.ResourceRecordSet {
+	list, err := provider.List()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	return list
+}
+
+// applyChanges submits changes to the zone of provider unless running
+// dry, waiting for them to be applied, and answered by all nameservers,
+// if required.
+func applyChanges(
+	provider got.Provider,
+	changes []*route53.Change,
+	sync bool,
+) {
+	if dryrun {
+		return
+	}
+	id, err := provider.Apply(changes)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	if sync || verify {
+		if err = provider.Wait(id); err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+	if verify {
+		verifyChanges(changes, provider)
+	}
+}
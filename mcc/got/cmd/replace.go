@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/got/got"
+)
+
+var replaceFrom, replaceTo string
+
+// replaceCmd represents the replace command
+var replaceCmd = &cobra.Command{
+	Use:   "replace [flags]",
+	Short: "Replace a value in all DNS records of a zone",
+	Long: `Search all record values and alias targets in a zone for a value,
+and UPSERT the record sets found with the new value in a single batch.
+The resulting record sets are printed before being applied.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(replaceFrom) <= 0 || len(replaceTo) <= 0 {
+			log.Fatal("You must specify both from and to values")
+		}
+		provider := selectProvider()
+		list := listRecords(provider)
+		changes := got.ReplaceChangeList(list, replaceFrom, replaceTo, typ)
+		if len(changes) <= 0 {
+			log.Fatalf("No records found pointing to %s", replaceFrom)
+		}
+		fmt.Print(got.DescribeChanges(changes, provider.Zone()))
+		applyChanges(provider, changes, wait)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(replaceCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// replaceCmd.PersistentFlags().String("foo", "", "A help for foo")
+	replaceCmd.PersistentFlags().BoolVarP(
+		&dryrun,
+		"dryrun",
+		"",
+		false,
+		"Don't really do anything",
+	)
+	replaceCmd.PersistentFlags().BoolVarP(
+		&wait,
+		"wait",
+		"",
+		false,
+		"Don't return until operation is completed",
+	)
+	replaceCmd.PersistentFlags().BoolVarP(
+		&verify,
+		"verify",
+		"",
+		false,
+		"Wait until all zone nameservers answer the change",
+	)
+	replaceCmd.PersistentFlags().DurationVarP(
+		&verifyTimeout,
+		"verify-timeout",
+		"",
+		5*time.Minute,
+		"Time to wait for nameservers to answer the change",
+	)
+	replaceCmd.PersistentFlags().StringVarP(
+		&replaceFrom,
+		"from",
+		"",
+		"",
+		"Value to search for.",
+	)
+	replaceCmd.PersistentFlags().StringVarP(
+		&replaceTo,
+		"to",
+		"",
+		"",
+		"Value to replace with.",
+	)
+	replaceCmd.PersistentFlags().StringVarP(
+		&typ,
+		"type",
+		"",
+		"",
+		"Type of the records to replace values of.",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// replaceCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
@@ -42,6 +42,62 @@ func init() {
 	// will be global for your application.
 
 	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.got.yaml)")
+	RootCmd.PersistentFlags().StringVarP(
+		&zoneName,
+		"zone",
+		"",
+		"",
+		"Name of the zone to work on.",
+	)
+	RootCmd.PersistentFlags().StringVarP(
+		&zoneID,
+		"zone-id",
+		"",
+		"",
+		"ID of the zone to work on.",
+	)
+	RootCmd.PersistentFlags().BoolVarP(
+		&private,
+		"private",
+		"",
+		false,
+		"Whether the zone to work on is private. Both are accepted if unset.",
+	)
+	RootCmd.PersistentFlags().StringVarP(
+		&providerName,
+		"provider",
+		"",
+		"route53",
+		"DNS provider of the zone to work on: route53 or rfc2136.",
+	)
+	RootCmd.PersistentFlags().StringVarP(
+		&server,
+		"server",
+		"",
+		"",
+		"Address of the DNS server accepting RFC 2136 updates.",
+	)
+	RootCmd.PersistentFlags().StringVarP(
+		&tsigName,
+		"tsig-name",
+		"",
+		"",
+		"Name of the TSIG key to sign RFC 2136 requests with.",
+	)
+	RootCmd.PersistentFlags().StringVarP(
+		&tsigSecret,
+		"tsig-secret",
+		"",
+		"",
+		"Base64 encoded TSIG key secret. Defaults to GOT_TSIG_SECRET.",
+	)
+	RootCmd.PersistentFlags().StringVarP(
+		&tsigAlgorithm,
+		"tsig-algorithm",
+		"",
+		"hmac-sha256",
+		"Algorithm of the TSIG key.",
+	)
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	RootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/got/got"
+)
+
+var shiftFrom, shiftTo, steps string
+var interval time.Duration
+
+// shiftControl returns a channel receiving commands typed in standard
+// input, or their initials, and abort on interrupt.
+func shiftControl() <-chan string {
+	control := make(chan string, 1)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		for range interrupt {
+			control <- got.ShiftAbort
+		}
+	}()
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			input := strings.TrimSpace(scanner.Text())
+			for _, command := range []string{
+				got.ShiftPause,
+				got.ShiftResume,
+				got.ShiftAbort,
+			} {
+				if input != "" && strings.HasPrefix(command, input) {
+					control <- command
+				}
+			}
+		}
+	}()
+	return control
+}
+
+// shiftCmd represents the shift command
+var shiftCmd = &cobra.Command{
+	Use:   "shift [flags]",
+	Short: "Shift traffic gradually in between weighted DNS records",
+	Long: `Rewrite the weights of two weighted record sets step by step, waiting
+for every step to be in sync and for an interval in between steps.
+Type pause, resume, or abort (or their initials) and press enter to
+control the shift. Aborting, also with Ctrl-C, restores the original
+weights, as does failing to apply a step. Running dry prints the changes
+of every step without waiting in between them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(name) <= 0 {
+			log.Fatal("No record name specified")
+		}
+		if len(shiftFrom) <= 0 || len(shiftTo) <= 0 {
+			log.Fatal("You must specify both from and to set identifiers")
+		}
+		percentages, err := got.ParseSteps(steps)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		provider := selectProvider()
+		list := listRecords(provider)
+		shift, err := got.NewShift(list, name, typ, shiftFrom, shiftTo)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		apply := func(changes []*route53.Change) error {
+			if dryrun {
+				fmt.Print(got.DescribeChanges(changes, provider.Zone()))
+				return nil
+			}
+			id, err := provider.Apply(changes)
+			if err != nil {
+				return err
+			}
+			return provider.Wait(id)
+		}
+		if dryrun {
+			// Nothing changes in between steps, so there's no need to wait
+			interval = 0
+		}
+		err = shift.Run(percentages, interval, apply, shiftControl())
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(shiftCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// shiftCmd.PersistentFlags().String("foo", "", "A help for foo")
+	shiftCmd.PersistentFlags().BoolVarP(
+		&dryrun,
+		"dryrun",
+		"",
+		false,
+		"Don't really do anything",
+	)
+	shiftCmd.PersistentFlags().StringVarP(
+		&name,
+		"name",
+		"",
+		"",
+		"Name of the weighted records.",
+	)
+	shiftCmd.PersistentFlags().StringVarP(
+		&typ,
+		"type",
+		"",
+		"",
+		"Type of the weighted records.",
+	)
+	shiftCmd.PersistentFlags().StringVarP(
+		&shiftFrom,
+		"from",
+		"",
+		"",
+		"Set identifier of the record to shift traffic from.",
+	)
+	shiftCmd.PersistentFlags().StringVarP(
+		&shiftTo,
+		"to",
+		"",
+		"",
+		"Set identifier of the record to shift traffic to.",
+	)
+	shiftCmd.PersistentFlags().StringVarP(
+		&steps,
+		"steps",
+		"",
+		"10,25,50,100",
+		"Comma separated percentages of traffic to shift on each step.",
+	)
+	shiftCmd.PersistentFlags().DurationVarP(
+		&interval,
+		"interval",
+		"",
+		10*time.Minute,
+		"Time to wait in between steps.",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// shiftCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/got/got"
+)
+
+var templateVars []string
+
+// templateCmd represents the template command
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage DNS records from templates",
+	Long:  ``,
+}
+
+// templateApplyCmd represents the template apply command
+var templateApplyCmd = &cobra.Command{
+	Use:   "apply [flags] <template>",
+	Short: "UPSERT the DNS records in a template",
+	Long: `Render a template file and UPSERT the record sets in it in a single
+batch. Templates are snapshots as exported by got export, using Go
+text/template syntax to refer to variables passed with --var name=value,
+and to the zone variable holding the zone name, which are escaped to be
+used within JSON strings. Record names not ending
+with a dot are relative to the zone, "@" being the zone itself.
+The resulting record sets are printed before being applied.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			log.Fatal("You must specify a template file")
+		}
+		text, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		vars, err := got.ParseVars(templateVars)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		provider := selectProvider()
+		list, err := got.RenderTemplate(string(text), provider.Zone(), vars)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		changes, err := got.TemplateChangeList(list, provider.Zone())
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		if len(changes) <= 0 {
+			log.Fatal("No records found in template")
+		}
+		fmt.Print(got.DescribeChanges(changes, provider.Zone()))
+		applyChanges(provider, changes, wait)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateApplyCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// templateCmd.PersistentFlags().String("foo", "", "A help for foo")
+	templateApplyCmd.PersistentFlags().StringArrayVarP(
+		&templateVars,
+		"var",
+		"",
+		[]string{},
+		"Template variable, as name=value. Can be repeated.",
+	)
+	templateApplyCmd.PersistentFlags().BoolVarP(
+		&dryrun,
+		"dryrun",
+		"",
+		false,
+		"Don't really do anything",
+	)
+	templateApplyCmd.PersistentFlags().BoolVarP(
+		&wait,
+		"wait",
+		"",
+		false,
+		"Don't return until operation is completed",
+	)
+	templateApplyCmd.PersistentFlags().BoolVarP(
+		&verify,
+		"verify",
+		"",
+		false,
+		"Wait until all zone nameservers answer the change",
+	)
+	templateApplyCmd.PersistentFlags().DurationVarP(
+		&verifyTimeout,
+		"verify-timeout",
+		"",
+		5*time.Minute,
+		"Time to wait for nameservers to answer the change",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// templateCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
@@ -10,7 +10,6 @@ import (
 )
 
 var dryrun, exclude, wait, filterByName, filterByType bool
-var zoneName string
 var ttl int64
 
 // ttlCmd represents the ttl command
@@ -19,13 +18,9 @@ var ttlCmd = &cobra.Command{
 	Short: "Modify Time To Live of a set of records in a DNS zone",
 	Long:  ``,
 	Run: func(cmd *cobra.Command, args []string) {
-		svc := got.Init()
-		if len(zoneName) <= 0 {
-			log.Fatal("No zone name specified")
-		}
-		zoneID := got.GetZoneID(zoneName, svc)
+		provider := selectProvider()
 
-		list := got.GetResourceRecordSet(zoneID, svc)
+		list := listRecords(provider)
 		// Filter list in between
 		if exclude && filterByType {
 			list = got.FilterResourceRecords(
@@ -84,21 +79,10 @@ var ttlCmd = &cobra.Command{
 				},
 			)
 		}
-		changeResponse, err := got.UpsertResourceRecordSetTTL(
-			list,
-			ttl,
-			&zoneID,
-			svc,
-		)
-		if err != nil {
-			log.Panic(err.Error())
-		}
-		if wait && !dryrun {
-			got.WaitForChangeToComplete(
-				changeResponse.ChangeInfo,
-				svc,
-			)
+		if len(list) <= 0 {
+			log.Fatal("No records to process.")
 		}
+		applyChanges(provider, got.TTLChangeList(list, ttl), wait)
 	},
 }
 
@@ -131,13 +115,6 @@ func init() {
 		false,
 		"Don't return until operation is completed",
 	)
-	ttlCmd.PersistentFlags().StringVarP(
-		&zoneName,
-		"zone",
-		"",
-		"",
-		"Name of the zone to work on.",
-	)
 	ttlCmd.PersistentFlags().Int64VarP(
 		&ttl,
 		"ttl",
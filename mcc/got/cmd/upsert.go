@@ -2,13 +2,15 @@ package cmd
 
 import (
 	"log"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/poka-yoke/spaceflight/mcc/got/got"
 )
 
-var name, typ string
+var name, typ, setID, failover, healthCheckID string
+var weight int64
 
 // upsertCmd represents the upsert command
 var upsertCmd = &cobra.Command{
@@ -16,10 +18,6 @@ var upsertCmd = &cobra.Command{
 	Short: "Upsert a DNS record",
 	Long:  ``,
 	Run: func(cmd *cobra.Command, args []string) {
-		svc := got.Init()
-		if len(zoneName) <= 0 {
-			log.Fatal("No zone name specified")
-		}
 		if len(name) <= 0 {
 			log.Fatal("No record name specified")
 		}
@@ -29,16 +27,19 @@ var upsertCmd = &cobra.Command{
 		if len(args) <= 0 {
 			log.Fatal("No destination specified")
 		}
-		zoneid := got.GetZoneID(zoneName, svc)
+		provider := selectProvider()
 		list := got.NewResourceRecordList(args)
 		changes := got.UpsertChangeList(list, ttl, name, typ)
-		if !dryrun {
-			res, err := got.ApplyChanges(changes, &zoneid, svc)
-			if err != nil {
-				log.Fatal(err.Error())
-			}
-			log.Println(res)
+		options := got.RecordSetOptions{
+			SetIdentifier: setID,
+			Failover:      failover,
+			HealthCheckID: healthCheckID,
+		}
+		if cmd.Flags().Changed("weight") {
+			options.Weight = &weight
 		}
+		options.Apply(changes)
+		applyChanges(provider, changes, wait)
 	},
 }
 
@@ -71,12 +72,19 @@ func init() {
 		false,
 		"Don't return until operation is completed",
 	)
-	upsertCmd.PersistentFlags().StringVarP(
-		&zoneName,
-		"zone",
+	upsertCmd.PersistentFlags().BoolVarP(
+		&verify,
+		"verify",
 		"",
+		false,
+		"Wait until all zone nameservers answer the change",
+	)
+	upsertCmd.PersistentFlags().DurationVarP(
+		&verifyTimeout,
+		"verify-timeout",
 		"",
-		"Name of the zone to work on.",
+		5*time.Minute,
+		"Time to wait for nameservers to answer the change",
 	)
 	upsertCmd.PersistentFlags().Int64VarP(
 		&ttl,
@@ -99,6 +107,34 @@ func init() {
 		"",
 		"Type of the record to upsert.",
 	)
+	upsertCmd.PersistentFlags().StringVarP(
+		&setID,
+		"set-id",
+		"",
+		"",
+		"Set identifier of weighted or failover records.",
+	)
+	upsertCmd.PersistentFlags().StringVarP(
+		&failover,
+		"failover",
+		"",
+		"",
+		"Failover role of the record: PRIMARY or SECONDARY.",
+	)
+	upsertCmd.PersistentFlags().Int64VarP(
+		&weight,
+		"weight",
+		"",
+		0,
+		"Weight of weighted records.",
+	)
+	upsertCmd.PersistentFlags().StringVarP(
+		&healthCheckID,
+		"health-check",
+		"",
+		"",
+		"ID of the health check to attach to the record.",
+	)
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+
+	"github.com/poka-yoke/spaceflight/mcc/got/got"
+)
+
+var verify bool
+var verifyTimeout time.Duration
+
+// verifyChanges waits until every nameserver of the zone answers the
+// changes, and exits reporting the ones lagging behind otherwise.
+func verifyChanges(
+	changes []*route53.Change,
+	provider got.Provider,
+) {
+	nameservers, err := provider.NameServers()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	lagging := got.WaitForPropagation(
+		nameservers,
+		got.NewExpectations(changes),
+		got.NewDNSResolver(),
+		verifyTimeout,
+	)
+	if len(lagging) > 0 {
+		log.Fatalf(
+			"Nameservers lagging behind: %s",
+			strings.Join(lagging, ", "),
+		)
+	}
+	log.Printf("Changes answered by %d nameservers\n", len(nameservers))
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/got/got"
+)
+
+var zoneName, zoneID, comment, vpcID, vpcRegion string
+var private, force bool
+
+// selectZone returns the ID and name of the hosted zone selected through
+// the global zone flags, exiting if there's not exactly one.
+func selectZone(svc route53iface.Route53API) (id, name string) {
+	selector := got.ZoneSelector{
+		Name:    zoneName,
+		ID:      zoneID,
+		Private: privateFlag(),
+	}
+	zone, err := got.FindHostedZone(selector, svc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	return *zone.Id, *zone.Name
+}
+
+// privateFlag returns whether the zone to work on is private, or nil if
+// it wasn't specified.
+func privateFlag() *bool {
+	if RootCmd.PersistentFlags().Changed("private") {
+		return aws.Bool(private)
+	}
+	return nil
+}
+
+// zoneCmd represents the zone command
+var zoneCmd = &cobra.Command{
+	Use:   "zone",
+	Short: "Manage DNS zones",
+	Long:  ``,
+}
+
+// zoneListCmd represents the zone list command
+var zoneListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List DNS zones",
+	Long:  `List ID, name, visibility and record count of all hosted zones.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		svc := got.Init()
+		zones, err := got.ListHostedZones(svc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		fmt.Print(got.FormatHostedZones(zones))
+	},
+}
+
+// zoneCreateCmd represents the zone create command
+var zoneCreateCmd = &cobra.Command{
+	Use:   "create [flags] <name>",
+	Short: "Create a DNS zone",
+	Long: `Create a hosted zone. Zones associated to a VPC with --vpc-id are
+private.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		svc := got.Init()
+		if len(args) != 1 {
+			log.Fatal("You must specify the name of the zone")
+		}
+		var vpc *got.VPC
+		if len(vpcID) > 0 {
+			vpc = &got.VPC{ID: vpcID, Region: vpcRegion}
+		}
+		if dryrun {
+			fmt.Print(got.DescribeHostedZone(args[0], comment, vpc))
+			return
+		}
+		res, err := got.CreateHostedZone(args[0], comment, vpc, svc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		log.Println(res.ChangeInfo)
+		if wait {
+			got.WaitForChangeToComplete(res.ChangeInfo, svc)
+		}
+		fmt.Println(*res.HostedZone.Id)
+		if res.DelegationSet != nil {
+			for _, ns := range res.DelegationSet.NameServers {
+				fmt.Println(*ns)
+			}
+		}
+	},
+}
+
+// zoneDeleteCmd represents the zone delete command
+var zoneDeleteCmd = &cobra.Command{
+	Use:   "delete [flags]",
+	Short: "Delete a DNS zone",
+	Long: `Delete the selected hosted zone. Zones containing records other than
+its SOA and NS ones are only deleted with --force, which removes them first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		svc := got.Init()
+		zoneid, apex := selectZone(svc)
+		changes := got.EmptyChangeList(
+			got.GetResourceRecordSet(zoneid, svc),
+			apex,
+		)
+		if len(changes) > 0 && !force {
+			log.Fatalf(
+				"Zone %s still has %d records, use --force to delete them",
+				apex,
+				len(changes),
+			)
+		}
+		if dryrun {
+			fmt.Print(got.DescribeChanges(changes, apex))
+			fmt.Print(got.DescribeHostedZoneDeletion(zoneid, apex))
+			return
+		}
+		if len(changes) > 0 {
+			res, err := got.ApplyChanges(changes, &zoneid, svc)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			got.WaitForChangeToComplete(res.ChangeInfo, svc)
+		}
+		res, err := got.DeleteHostedZone(zoneid, svc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		log.Println(res.ChangeInfo)
+		if wait {
+			got.WaitForChangeToComplete(res.ChangeInfo, svc)
+		}
+	},
+}
+
+// zoneAssociateVPCCmd represents the zone associate-vpc command
+var zoneAssociateVPCCmd = &cobra.Command{
+	Use:   "associate-vpc [flags]",
+	Short: "Associate a private DNS zone with a VPC",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		svc := got.Init()
+		if len(vpcID) <= 0 {
+			log.Fatal("No VPC ID specified")
+		}
+		zoneid, apex := selectZone(svc)
+		vpc := got.VPC{ID: vpcID, Region: vpcRegion}
+		if dryrun {
+			fmt.Print(got.DescribeVPCAssociation(zoneid, apex, vpc))
+			return
+		}
+		res, err := got.AssociateVPC(zoneid, vpc, svc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		log.Println(res.ChangeInfo)
+		if wait {
+			got.WaitForChangeToComplete(res.ChangeInfo, svc)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(zoneCmd)
+	zoneCmd.AddCommand(zoneListCmd)
+	zoneCmd.AddCommand(zoneCreateCmd)
+	zoneCmd.AddCommand(zoneDeleteCmd)
+	zoneCmd.AddCommand(zoneAssociateVPCCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// zoneCmd.PersistentFlags().String("foo", "", "A help for foo")
+	zoneCmd.PersistentFlags().BoolVarP(
+		&dryrun,
+		"dryrun",
+		"",
+		false,
+		"Don't really do anything",
+	)
+	zoneCmd.PersistentFlags().BoolVarP(
+		&wait,
+		"wait",
+		"",
+		false,
+		"Don't return until operation is completed",
+	)
+	zoneCreateCmd.PersistentFlags().StringVarP(
+		&comment,
+		"comment",
+		"",
+		"",
+		"Comment describing the zone.",
+	)
+	zoneCreateCmd.PersistentFlags().StringVarP(
+		&vpcID,
+		"vpc-id",
+		"",
+		"",
+		"ID of the VPC to associate the zone to.",
+	)
+	zoneCreateCmd.PersistentFlags().StringVarP(
+		&vpcRegion,
+		"vpc-region",
+		"",
+		"us-east-1",
+		"Region of the VPC to associate the zone to.",
+	)
+	zoneAssociateVPCCmd.PersistentFlags().StringVarP(
+		&vpcID,
+		"vpc-id",
+		"",
+		"",
+		"ID of the VPC to associate the zone to.",
+	)
+	zoneAssociateVPCCmd.PersistentFlags().StringVarP(
+		&vpcRegion,
+		"vpc-region",
+		"",
+		"us-east-1",
+		"Region of the VPC to associate the zone to.",
+	)
+	zoneDeleteCmd.PersistentFlags().BoolVarP(
+		&force,
+		"force",
+		"",
+		false,
+		"Delete all records in the zone first",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// zoneCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
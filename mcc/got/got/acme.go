@@ -0,0 +1,169 @@
+package got
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+)
+
+// ChallengeTTL is the TTL for ACME DNS-01 challenge records.
+var ChallengeTTL int64 = 60
+
+// ChallengePrefix is the label ACME DNS-01 challenge records live at.
+const ChallengePrefix = "_acme-challenge."
+
+// ChallengeName returns the fully qualified name of the ACME DNS-01
+// challenge record for domain. Wildcard domains share their base domain
+// challenge, and names already being a challenge are left as they are.
+func ChallengeName(domain string) string {
+	domain = strings.TrimPrefix(domain, "*.")
+	if !strings.HasPrefix(domain, ChallengePrefix) {
+		domain = ChallengePrefix + domain
+	}
+	if !strings.HasSuffix(domain, ".") {
+		domain += "."
+	}
+	return domain
+}
+
+// FindZoneForName returns the hosted zone the name belongs to, which is
+// the one with the longest name being a suffix of it. Private allows to
+// choose in between public and private zones sharing the same name.
+func FindZoneForName(
+	name string,
+	private *bool,
+	svc route53iface.Route53API,
+) (zone *route53.HostedZone, err error) {
+	zones, err := ListHostedZones(svc)
+	if err != nil {
+		return
+	}
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	matches := []*route53.HostedZone{}
+	for _, candidate := range zones {
+		if private != nil && *private != IsPrivate(candidate) {
+			continue
+		}
+		zoneName := strings.ToLower(strings.TrimSuffix(*candidate.Name, "."))
+		if name != zoneName && !strings.HasSuffix(name, "."+zoneName) {
+			continue
+		}
+		if len(matches) > 0 && len(*matches[0].Name) > len(*candidate.Name) {
+			continue
+		}
+		if len(matches) > 0 && len(*matches[0].Name) < len(*candidate.Name) {
+			matches = nil
+		}
+		matches = append(matches, candidate)
+	}
+	switch len(matches) {
+	case 0:
+		err = fmt.Errorf("No hosted zone found for %s", name)
+	case 1:
+		zone = matches[0]
+	default:
+		err = fmt.Errorf(
+			"Several hosted zones found for %s. "+
+				"Specify one of them by ID, or whether it's private",
+			name,
+		)
+	}
+	return
+}
+
+// findRecordSet returns the record set in list with name and type, if any.
+func findRecordSet(
+	list []*route53.ResourceRecordSet,
+	name string,
+	typ string,
+) *route53.ResourceRecordSet {
+	for _, set := range list {
+		if sameName(*set.Name, name) && *set.Type == typ &&
+			set.SetIdentifier == nil {
+			return set
+		}
+	}
+	return nil
+}
+
+// quoteToken returns token as a TXT record value.
+func quoteToken(token string) string {
+	return fmt.Sprintf("\"%s\"", strings.Trim(token, "\""))
+}
+
+// PresentChallengeChangeList generates a list of changes for adding token
+// to the challenge record name, keeping other tokens already present, as
+// several validations for the same name can be in progress.
+func PresentChallengeChangeList(
+	list []*route53.ResourceRecordSet,
+	name string,
+	token string,
+) (res []*route53.Change) {
+	value := quoteToken(token)
+	values := []string{value}
+	if set := findRecordSet(list, name, route53.RRTypeTxt); set != nil {
+		for _, record := range set.ResourceRecords {
+			if *record.Value == value {
+				return
+			}
+			values = append(values, *record.Value)
+		}
+	}
+	return []*route53.Change{
+		{
+			Action: aws.String(route53.ChangeActionUpsert),
+			ResourceRecordSet: &route53.ResourceRecordSet{
+				Name:            aws.String(name),
+				Type:            aws.String(route53.RRTypeTxt),
+				TTL:             aws.Int64(ChallengeTTL),
+				ResourceRecords: NewResourceRecordList(values),
+			},
+		},
+	}
+}
+
+// CleanupChallengeChangeList generates a list of changes for removing
+// token from the challenge record name, deleting the record if it was
+// the only token present.
+func CleanupChallengeChangeList(
+	list []*route53.ResourceRecordSet,
+	name string,
+	token string,
+) (res []*route53.Change) {
+	set := findRecordSet(list, name, route53.RRTypeTxt)
+	if set == nil {
+		return
+	}
+	value := quoteToken(token)
+	values := []string{}
+	for _, record := range set.ResourceRecords {
+		if *record.Value != value {
+			values = append(values, *record.Value)
+		}
+	}
+	if len(values) == len(set.ResourceRecords) {
+		return
+	}
+	if len(values) == 0 {
+		return []*route53.Change{
+			{
+				Action:            aws.String(route53.ChangeActionDelete),
+				ResourceRecordSet: set,
+			},
+		}
+	}
+	return []*route53.Change{
+		{
+			Action: aws.String(route53.ChangeActionUpsert),
+			ResourceRecordSet: &route53.ResourceRecordSet{
+				Name:            set.Name,
+				Type:            set.Type,
+				TTL:             set.TTL,
+				ResourceRecords: NewResourceRecordList(values),
+			},
+		},
+	}
+}
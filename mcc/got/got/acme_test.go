@@ -0,0 +1,95 @@
+package got
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+var cntest = []struct {
+	domain, name string
+}{
+	{"example.com", "_acme-challenge.example.com."},
+	{"*.example.com", "_acme-challenge.example.com."},
+	{"www.example.com.", "_acme-challenge.www.example.com."},
+	{"_acme-challenge.example.com.", "_acme-challenge.example.com."},
+}
+
+func TestChallengeName(t *testing.T) {
+	for _, tt := range cntest {
+		t.Run(tt.domain, func(t *testing.T) {
+			if name := ChallengeName(tt.domain); name != tt.name {
+				t.Errorf("Expected %s, got %s", tt.name, name)
+			}
+		})
+	}
+}
+
+func TestFindZoneForName(t *testing.T) {
+	client := &mockZonesClient{zones: zonesClient.zones}
+	var fzfntest = []struct {
+		name    string
+		private *bool
+		id      string
+	}{
+		{"_acme-challenge.www.sub.example.com.", nil, "/hostedzone/Z3"},
+		{"_acme-challenge.example.org", nil, "/hostedzone/Z4"},
+		{"_acme-challenge.example.com.", aws.Bool(true), "/hostedzone/Z2"},
+		{"_acme-challenge.example.com.", aws.Bool(false), "/hostedzone/Z1"},
+		{"_acme-challenge.example.com.", nil, ""},
+		{"_acme-challenge.example.net.", nil, ""},
+	}
+	for _, tt := range fzfntest {
+		t.Run(tt.name, func(t *testing.T) {
+			zone, err := FindZoneForName(tt.name, tt.private, client)
+			if tt.id == "" {
+				if err == nil {
+					t.Errorf("Expected failure, got %s", *zone.Id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *zone.Id != tt.id {
+				t.Errorf("Expected %s, got %s", tt.id, *zone.Id)
+			}
+		})
+	}
+}
+
+func TestChallengeChangeLists(t *testing.T) {
+	name := "_acme-challenge.example.com."
+	changes := PresentChallengeChangeList(nil, name, "first")
+	expected := "UPSERT _acme-challenge TXT 60 \"first\"\n"
+	if out := DescribeChanges(changes, "example.com"); out != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out)
+	}
+	list := []*route53.ResourceRecordSet{changes[0].ResourceRecordSet}
+	changes = PresentChallengeChangeList(list, name, "second")
+	expected = "UPSERT _acme-challenge TXT 60 \"first\" \"second\"\n"
+	if out := DescribeChanges(changes, "example.com"); out != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out)
+	}
+	if len(PresentChallengeChangeList(list, name, "first")) != 0 {
+		t.Error("Present tokens should not be changed")
+	}
+	list = []*route53.ResourceRecordSet{changes[0].ResourceRecordSet}
+	changes = CleanupChallengeChangeList(list, name, "first")
+	expected = "UPSERT _acme-challenge TXT 60 \"second\"\n"
+	if out := DescribeChanges(changes, "example.com"); out != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out)
+	}
+	list = []*route53.ResourceRecordSet{changes[0].ResourceRecordSet}
+	changes = CleanupChallengeChangeList(list, name, "second")
+	if len(changes) != 1 || *changes[0].Action != "DELETE" {
+		t.Errorf("Last token should delete the record")
+	}
+	if len(CleanupChallengeChangeList(list, name, "missing")) != 0 {
+		t.Error("Missing tokens should not be changed")
+	}
+	if len(CleanupChallengeChangeList(nil, name, "second")) != 0 {
+		t.Error("Missing records should not be changed")
+	}
+}
@@ -0,0 +1,221 @@
+package got
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/miekg/dns"
+)
+
+// RootServers are the nameservers delegations are looked up from when
+// the parent zone isn't available otherwise.
+var RootServers = []string{
+	"a.root-servers.net.",
+	"b.root-servers.net.",
+	"c.root-servers.net.",
+}
+
+// ParentName returns the name of the zone name is delegated from, or ""
+// for top level domains.
+func ParentName(name string) string {
+	name = strings.TrimSuffix(name, ".")
+	i := strings.Index(name, ".")
+	if i < 0 {
+		return ""
+	}
+	return name[i+1:] + "."
+}
+
+// RecordValues returns the values of the name record of type typ in list.
+func RecordValues(
+	list []*route53.ResourceRecordSet,
+	name, typ string,
+) (values []string) {
+	if set := findRecordSet(list, name, typ); set != nil {
+		for _, record := range set.ResourceRecords {
+			values = append(values, *record.Value)
+		}
+	}
+	return
+}
+
+// SubdomainDelegations returns the NS record sets in list delegating
+// subdomains of apex.
+func SubdomainDelegations(
+	list []*route53.ResourceRecordSet,
+	apex string,
+) (res []*route53.ResourceRecordSet) {
+	for _, set := range list {
+		if *set.Type == route53.RRTypeNs && !sameName(*set.Name, apex) {
+			res = append(res, set)
+		}
+	}
+	return
+}
+
+// CompareNameServers returns a problem for every nameserver in only one
+// of the sets a zone has and its parent delegates to.
+func CompareNameServers(
+	zone string,
+	own, delegated []string,
+) (problems []string) {
+	ownSet := map[string]bool{}
+	for _, ns := range normalizeValues(own) {
+		ownSet[ns] = true
+	}
+	delegatedSet := map[string]bool{}
+	for _, ns := range normalizeValues(delegated) {
+		delegatedSet[ns] = true
+		if !ownSet[ns] {
+			problems = append(problems, fmt.Sprintf(
+				"%s: %s is delegated to but not in the zone NS record",
+				zone,
+				ns,
+			))
+		}
+	}
+	for _, ns := range normalizeValues(own) {
+		if !delegatedSet[ns] {
+			problems = append(problems, fmt.Sprintf(
+				"%s: %s is in the zone NS record but not delegated to",
+				zone,
+				ns,
+			))
+		}
+	}
+	return
+}
+
+// soaSerial returns the serial number of a SOA record value.
+func soaSerial(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) < 3 {
+		return ""
+	}
+	return fields[2]
+}
+
+// CheckNameServers returns a problem for every nameserver not answering
+// the zone SOA record, which makes it a lame delegation, and for every
+// one answering a different serial than soa's, unless soa is empty.
+func CheckNameServers(
+	zone string,
+	soa string,
+	nameservers []string,
+	resolver Resolver,
+) (problems []string) {
+	for _, ns := range normalizeValues(nameservers) {
+		values, err := resolver.Query(ns, zone, route53.RRTypeSoa)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf(
+				"%s: %s is lame: %s",
+				zone,
+				ns,
+				err,
+			))
+			continue
+		}
+		if len(values) == 0 {
+			problems = append(problems, fmt.Sprintf(
+				"%s: %s is lame: no SOA record answered",
+				zone,
+				ns,
+			))
+			continue
+		}
+		if soa != "" && soaSerial(values[0]) != soaSerial(soa) {
+			problems = append(problems, fmt.Sprintf(
+				"%s: %s answers SOA serial %s instead of %s",
+				zone,
+				ns,
+				soaSerial(values[0]),
+				soaSerial(soa),
+			))
+		}
+	}
+	return
+}
+
+// CheckDelegation returns the problems of the delegation of the zone
+// named apex, whose record sets are in list, to nameservers delegated.
+func CheckDelegation(
+	apex string,
+	list []*route53.ResourceRecordSet,
+	delegated []string,
+	resolver Resolver,
+) (problems []string) {
+	if len(delegated) == 0 {
+		return []string{fmt.Sprintf("%s: not delegated by its parent", apex)}
+	}
+	problems = CompareNameServers(
+		apex,
+		RecordValues(list, apex, route53.RRTypeNs),
+		delegated,
+	)
+	soa := ""
+	if values := RecordValues(list, apex, route53.RRTypeSoa); len(values) > 0 {
+		soa = values[0]
+	}
+	return append(
+		problems,
+		CheckNameServers(apex, soa, delegated, resolver)...,
+	)
+}
+
+// LookupDelegation returns the nameservers name is delegated to, following
+// referrals from servers down every label of name.
+func LookupDelegation(
+	name string,
+	servers []string,
+	resolver Resolver,
+) (nameservers []string, err error) {
+	nameservers, delegated, err := lookupReferrals(name, servers, resolver)
+	if err != nil {
+		return
+	}
+	if !delegated {
+		return nil, fmt.Errorf("No delegation found for %s", dns.Fqdn(name))
+	}
+	return
+}
+
+// lookupReferrals follows referrals from servers down every label of
+// name, and returns the nameservers of the zone name is in, and whether
+// name is the zone itself. Labels which aren't zone cuts answer no
+// referral, so they're asked to the same servers.
+func lookupReferrals(
+	name string,
+	servers []string,
+	resolver Resolver,
+) (nameservers []string, delegated bool, err error) {
+	labels := dnsLabels(name)
+	for i := len(labels) - 1; i >= 0; i-- {
+		suffix := strings.Join(labels[i:], ".") + "."
+		var referral []string
+		answered := false
+		for _, server := range servers {
+			referral, err = resolver.Query(server, suffix, route53.RRTypeNs)
+			if err == nil {
+				answered = true
+				break
+			}
+		}
+		if !answered {
+			return
+		}
+		delegated = len(referral) > 0
+		if delegated {
+			servers = referral
+		}
+	}
+	nameservers = append([]string{}, servers...)
+	sort.Strings(nameservers)
+	return
+}
+
+// dnsLabels returns the labels of name.
+func dnsLabels(name string) []string {
+	return strings.Split(strings.TrimSuffix(name, "."), ".")
+}
@@ -0,0 +1,136 @@
+package got
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+var parentnametests = []struct {
+	name     string
+	expected string
+}{
+	{"example.com.", "com."},
+	{"sub.example.com", "example.com."},
+	{"com.", ""},
+}
+
+func TestParentName(t *testing.T) {
+	for _, tt := range parentnametests {
+		t.Run(tt.name, func(t *testing.T) {
+			if out := ParentName(tt.name); out != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, out)
+			}
+		})
+	}
+}
+
+var delegationZone = []*route53.ResourceRecordSet{
+	newRecordSet("example.com.", "SOA", 900, "ns1.example.net. admin.example.com. 5 7200 900 1209600 86400"),
+	newRecordSet("example.com.", "NS", 172800, "ns1.example.net.", "ns2.example.net."),
+	newRecordSet("www.example.com.", "A", 300, "10.0.0.1"),
+	newRecordSet("sub.example.com.", "NS", 300, "ns3.example.net."),
+}
+
+var checkdelegationtests = []struct {
+	name      string
+	delegated []string
+	answers   map[string]map[string][]string
+	problems  []string
+}{
+	{
+		name:      "consistent",
+		delegated: []string{"ns1.example.net.", "ns2.example.net."},
+		answers: map[string]map[string][]string{
+			"ns1.example.net": {"example.com. SOA": {"ns1.example.net. admin.example.com. 5 7200 900 1209600 86400"}},
+			"ns2.example.net": {"example.com. SOA": {"ns1.example.net. admin.example.com. 5 7200 900 1209600 86400"}},
+		},
+		problems: nil,
+	},
+	{
+		name:      "mismatched and lame",
+		delegated: []string{"ns1.example.net.", "ns3.example.net."},
+		answers: map[string]map[string][]string{
+			"ns1.example.net": {"example.com. SOA": {"ns1.example.net. admin.example.com. 4 7200 900 1209600 86400"}},
+		},
+		problems: []string{
+			"example.com.: ns3.example.net is delegated to but not in the zone NS record",
+			"example.com.: ns2.example.net is in the zone NS record but not delegated to",
+			"example.com.: ns1.example.net answers SOA serial 4 instead of 5",
+			"example.com.: ns3.example.net is lame: no SOA record answered",
+		},
+	},
+	{
+		name:      "not delegated",
+		delegated: nil,
+		problems:  []string{"example.com.: not delegated by its parent"},
+	},
+}
+
+func TestCheckDelegation(t *testing.T) {
+	for _, tt := range checkdelegationtests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := CheckDelegation(
+				"example.com.",
+				delegationZone,
+				tt.delegated,
+				&mockResolver{answers: tt.answers},
+			)
+			if !reflect.DeepEqual(problems, tt.problems) {
+				t.Errorf("Expected %q, got %q", tt.problems, problems)
+			}
+		})
+	}
+}
+
+func TestSubdomainDelegations(t *testing.T) {
+	sets := SubdomainDelegations(delegationZone, "example.com.")
+	if len(sets) != 1 || *sets[0].Name != "sub.example.com." {
+		t.Errorf("Expected only sub.example.com. delegation, got %v", sets)
+	}
+}
+
+func TestLookupDelegation(t *testing.T) {
+	resolver := &mockResolver{answers: map[string]map[string][]string{
+		"root": {"com. NS": {"tld."}},
+		"tld.": {"example.com. NS": {"ns2.example.net.", "ns1.example.net."}},
+	}}
+	nameservers, err := LookupDelegation("example.com.", []string{"root"}, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"ns1.example.net.", "ns2.example.net."}
+	if !reflect.DeepEqual(nameservers, expected) {
+		t.Errorf("Expected %v, got %v", expected, nameservers)
+	}
+	if _, err = LookupDelegation("other.com.", []string{"root"}, resolver); err == nil {
+		t.Error("Expected an error looking up an undelegated name")
+	}
+}
+
+func TestLookupDelegationSkipsLabels(t *testing.T) {
+	resolver := &mockResolver{answers: map[string]map[string][]string{
+		"root": {"com. NS": {"tld."}},
+		"tld.": {"example.com. NS": {"ns1.example.net."}},
+		"ns1.example.net.": {
+			"dev.internal.example.com. NS": {"ns3.example.net."},
+		},
+	}}
+	nameservers, err := LookupDelegation(
+		"dev.internal.example.com.",
+		[]string{"root"},
+		resolver,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"ns3.example.net."}
+	if !reflect.DeepEqual(nameservers, expected) {
+		t.Errorf("Expected %v, got %v", expected, nameservers)
+	}
+	_, err = LookupDelegation("internal.example.com.", []string{"root"}, resolver)
+	if err == nil {
+		t.Error("Expected an error looking up a name which isn't a zone cut")
+	}
+}
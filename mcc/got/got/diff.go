@@ -0,0 +1,277 @@
+package got
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// Snapshot holds the record sets of a zone, in the same format
+// `aws route53 list-resource-record-sets` outputs them.
+type Snapshot struct {
+	ResourceRecordSets []*route53.ResourceRecordSet
+}
+
+// WriteSnapshot writes list as a Snapshot in JSON format, leaving unset
+// fields out, as the AWS CLI does.
+func WriteSnapshot(w io.Writer, list []*route53.ResourceRecordSet) error {
+	out, err := json.Marshal(Snapshot{ResourceRecordSets: list})
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(out))
+	dec.UseNumber()
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	var compact, indented bytes.Buffer
+	if err = writeWithoutNulls(&compact, dec, t); err != nil {
+		return err
+	}
+	if err = json.Indent(&indented, compact.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", indented.Bytes())
+	return err
+}
+
+// writeWithoutNulls writes the JSON value starting with t, read from dec,
+// to buf, leaving out the object members whose value is null.
+func writeWithoutNulls(buf *bytes.Buffer, dec *json.Decoder, t json.Token) error {
+	switch t {
+	case json.Delim('{'), json.Delim('['):
+		object := t == json.Delim('{')
+		buf.WriteString(t.(json.Delim).String())
+		for first := true; dec.More(); {
+			var key json.Token
+			if object {
+				var err error
+				if key, err = dec.Token(); err != nil {
+					return err
+				}
+			}
+			value, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if object && value == nil {
+				continue
+			}
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			if object {
+				out, _ := json.Marshal(key)
+				buf.Write(out)
+				buf.WriteByte(':')
+			}
+			if err = writeWithoutNulls(buf, dec, value); err != nil {
+				return err
+			}
+		}
+		end, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		buf.WriteString(end.(json.Delim).String())
+		return nil
+	}
+	out, err := json.Marshal(t)
+	buf.Write(out)
+	return err
+}
+
+// ReadSnapshot returns the record sets in a Snapshot in JSON format.
+func ReadSnapshot(r io.Reader) ([]*route53.ResourceRecordSet, error) {
+	snapshot := Snapshot{}
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot.ResourceRecordSets, nil
+}
+
+// RelativeName returns name relative to apex, "@" being the apex itself.
+// Names out of apex are returned fully qualified.
+func RelativeName(name, apex string) string {
+	name = strings.ToLower(
+		strings.Replace(strings.TrimSuffix(name, "."), "\\052", "*", -1),
+	)
+	apex = strings.ToLower(strings.TrimSuffix(apex, "."))
+	if name == apex {
+		return "@"
+	}
+	if strings.HasSuffix(name, "."+apex) {
+		return strings.TrimSuffix(name, "."+apex)
+	}
+	return name + "."
+}
+
+// recordSetKey identifies a record set regardless of the zone it's in.
+func recordSetKey(set *route53.ResourceRecordSet, apex string) string {
+	key := fmt.Sprintf("%s %s", RelativeName(*set.Name, apex), *set.Type)
+	if set.SetIdentifier != nil {
+		key += fmt.Sprintf(" [%s]", *set.SetIdentifier)
+	}
+	return key
+}
+
+// recordSetData describes the data of a record set, excluding its key.
+func recordSetData(set *route53.ResourceRecordSet) string {
+	data := []string{}
+	if set.TTL != nil {
+		data = append(data, fmt.Sprintf("%d", *set.TTL))
+	}
+	if set.Weight != nil {
+		data = append(data, fmt.Sprintf("weight=%d", *set.Weight))
+	}
+	if set.Failover != nil {
+		data = append(data, fmt.Sprintf("failover=%s", *set.Failover))
+	}
+	if set.Region != nil {
+		data = append(data, fmt.Sprintf("region=%s", *set.Region))
+	}
+	if set.HealthCheckId != nil {
+		data = append(data, fmt.Sprintf("healthcheck=%s", *set.HealthCheckId))
+	}
+	if set.AliasTarget != nil {
+		data = append(
+			data,
+			fmt.Sprintf(
+				"ALIAS %s",
+				strings.ToLower(
+					strings.TrimSuffix(*set.AliasTarget.DNSName, "."),
+				),
+			),
+		)
+	}
+	values := []string{}
+	for _, record := range set.ResourceRecords {
+		values = append(values, *record.Value)
+	}
+	sort.Strings(values)
+	return strings.Join(append(data, values...), " ")
+}
+
+// RecordSetChange holds both versions of a record set found in two zones.
+type RecordSetChange struct {
+	From *route53.ResourceRecordSet
+	To   *route53.ResourceRecordSet
+}
+
+// ZoneDiff holds the differences between two lists of record sets.
+type ZoneDiff struct {
+	Added   []*route53.ResourceRecordSet
+	Removed []*route53.ResourceRecordSet
+	Changed []RecordSetChange
+	from    string
+	to      string
+}
+
+// Empty returns true if there are no differences.
+func (d *ZoneDiff) Empty() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Changed) == 0
+}
+
+// String lists differences one per line, prefixing additions with +,
+// removals with -, and changes with ~.
+func (d *ZoneDiff) String() (output string) {
+	lines := []string{}
+	for _, set := range d.Removed {
+		lines = append(
+			lines,
+			fmt.Sprintf("- %s %s", recordSetKey(set, d.from), recordSetData(set)),
+		)
+	}
+	for _, set := range d.Added {
+		lines = append(
+			lines,
+			fmt.Sprintf("+ %s %s", recordSetKey(set, d.to), recordSetData(set)),
+		)
+	}
+	for _, change := range d.Changed {
+		lines = append(
+			lines,
+			fmt.Sprintf(
+				"~ %s %s => %s",
+				recordSetKey(change.To, d.to),
+				recordSetData(change.From),
+				recordSetData(change.To),
+			),
+		)
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i][2:] < lines[j][2:]
+	})
+	for _, line := range lines {
+		output += line + "\n"
+	}
+	return
+}
+
+// DiffRecordSets compares record sets in zone from with the ones in zone
+// to. Names are compared relative to each zone's apex, so zones with
+// different names can be compared. Apex SOA and NS records are ignored
+// unless all is true, as they always differ in between zones.
+func DiffRecordSets(
+	from []*route53.ResourceRecordSet,
+	fromApex string,
+	to []*route53.ResourceRecordSet,
+	toApex string,
+	all bool,
+) *ZoneDiff {
+	diff := &ZoneDiff{from: fromApex, to: toApex}
+	remaining := map[string]*route53.ResourceRecordSet{}
+	for _, set := range from {
+		if all || !isApexAuthority(set, fromApex) {
+			remaining[recordSetKey(set, fromApex)] = set
+		}
+	}
+	for _, set := range to {
+		if !all && isApexAuthority(set, toApex) {
+			continue
+		}
+		key := recordSetKey(set, toApex)
+		old, ok := remaining[key]
+		if !ok {
+			diff.Added = append(diff.Added, set)
+			continue
+		}
+		delete(remaining, key)
+		if recordSetData(old) != recordSetData(set) {
+			diff.Changed = append(
+				diff.Changed,
+				RecordSetChange{From: old, To: set},
+			)
+		}
+	}
+	for _, set := range from {
+		if _, ok := remaining[recordSetKey(set, fromApex)]; ok {
+			diff.Removed = append(diff.Removed, set)
+		}
+	}
+	return diff
+}
+
+// isApexAuthority returns true for SOA and NS records at the zone apex.
+func isApexAuthority(set *route53.ResourceRecordSet, apex string) bool {
+	return RelativeName(*set.Name, apex) == "@" &&
+		(*set.Type == route53.RRTypeSoa || *set.Type == route53.RRTypeNs)
+}
+
+// ApexName returns the zone apex of a list of record sets, which is
+// the name of its SOA record.
+func ApexName(list []*route53.ResourceRecordSet) (string, error) {
+	for _, set := range list {
+		if *set.Type == route53.RRTypeSoa {
+			return *set.Name, nil
+		}
+	}
+	return "", fmt.Errorf("No SOA record found to tell zone apex")
+}
@@ -0,0 +1,150 @@
+package got
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// newRecordSet returns a record set for name, type, TTL and values.
+func newRecordSet(
+	name, typ string,
+	ttl int64,
+	values ...string,
+) *route53.ResourceRecordSet {
+	return &route53.ResourceRecordSet{
+		Name:            aws.String(name),
+		Type:            aws.String(typ),
+		TTL:             aws.Int64(ttl),
+		ResourceRecords: NewResourceRecordList(values),
+	}
+}
+
+var rntest = []struct {
+	name, apex, out string
+}{
+	{"www.example.com.", "example.com", "www"},
+	{"example.com.", "example.com.", "@"},
+	{"\\052.Example.com.", "example.com", "*"},
+	{"www.example.org.", "example.com", "www.example.org."},
+	{"notexample.com.", "example.com", "notexample.com."},
+}
+
+func TestRelativeName(t *testing.T) {
+	for _, tt := range rntest {
+		t.Run(tt.name, func(t *testing.T) {
+			if out := RelativeName(tt.name, tt.apex); out != tt.out {
+				t.Errorf("Expected %s, got %s", tt.out, out)
+			}
+		})
+	}
+}
+
+func TestDiffRecordSets(t *testing.T) {
+	staging := []*route53.ResourceRecordSet{
+		newRecordSet("staging.example.com.", "SOA", 900, "ns-1. admin. 1 7200 900 1209600 86400"),
+		newRecordSet("staging.example.com.", "NS", 172800, "ns-1."),
+		newRecordSet("www.staging.example.com.", "CNAME", 300, "lb-staging.example.com"),
+		newRecordSet("db.staging.example.com.", "A", 300, "10.0.0.1"),
+		newRecordSet("same.staging.example.com.", "A", 300, "10.0.0.3", "10.0.0.2"),
+	}
+	production := []*route53.ResourceRecordSet{
+		newRecordSet("example.com.", "SOA", 900, "ns-2. admin. 1 7200 900 1209600 86400"),
+		newRecordSet("example.com.", "NS", 172800, "ns-2."),
+		newRecordSet("www.example.com.", "CNAME", 300, "lb-production.example.com"),
+		newRecordSet("same.example.com.", "A", 300, "10.0.0.2", "10.0.0.3"),
+		newRecordSet("mail.example.com.", "MX", 300, "10 mx.example.com"),
+	}
+	diff := DiffRecordSets(
+		staging,
+		"staging.example.com",
+		production,
+		"example.com",
+		false,
+	)
+	expected := "- db A 300 10.0.0.1\n" +
+		"+ mail MX 300 10 mx.example.com\n" +
+		"~ www CNAME 300 lb-staging.example.com => 300 lb-production.example.com\n"
+	if diff.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, diff)
+	}
+	diff = DiffRecordSets(
+		staging,
+		"staging.example.com",
+		production,
+		"example.com",
+		true,
+	)
+	if len(diff.Changed) != 3 {
+		t.Errorf("Expected apex SOA and NS to differ:\n%s", diff)
+	}
+	diff = DiffRecordSets(staging, "staging.example.com", staging, "staging.example.com", true)
+	if !diff.Empty() {
+		t.Errorf("Zone should not differ from itself:\n%s", diff)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	buf := &bytes.Buffer{}
+	list := []*route53.ResourceRecordSet{
+		newRecordSet("example.com.", "SOA", 900, "ns-1. admin. 1 7200 900 1209600 86400"),
+		newRecordSet("www.example.com.", "A", 300, "10.0.0.1"),
+		{
+			Name: aws.String("cdn.example.com."),
+			Type: aws.String("A"),
+			AliasTarget: &route53.AliasTarget{
+				DNSName:              aws.String("d1.cloudfront.net."),
+				HostedZoneId:         aws.String("Z2FDTNDATAQYW2"),
+				EvaluateTargetHealth: aws.Bool(false),
+			},
+		},
+	}
+	if err := WriteSnapshot(buf, list); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "null") {
+		t.Errorf("Unset fields should be left out:\n%s", buf)
+	}
+	expected := `{
+  "ResourceRecordSets": [
+    {
+      "Name": "example.com.",
+      "ResourceRecords": [
+        {
+          "Value": "ns-1. admin. 1 7200 900 1209600 86400"
+        }
+      ],
+      "TTL": 900,
+      "Type": "SOA"
+    },`
+	if !strings.HasPrefix(buf.String(), expected) {
+		t.Errorf("Expected snapshot starting with:\n%s\nGot:\n%s", expected, buf)
+	}
+	out, err := ReadSnapshot(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := DiffRecordSets(list, "example.com", out, "example.com", true); !diff.Empty() {
+		t.Errorf("Snapshot doesn't match:\n%s", diff)
+	}
+	apex, err := ApexName(out)
+	if err != nil || apex != "example.com." {
+		t.Errorf("Unexpected apex %s (%v)", apex, err)
+	}
+	cli := `{"ResourceRecordSets": [{"Name": "www.example.com.", "Type": "A",
+		"AliasTarget": {"HostedZoneId": "Z1", "DNSName": "lb.example.com.",
+		"EvaluateTargetHealth": false}}]}`
+	out, err = ReadSnapshot(strings.NewReader(cli))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *out[0].AliasTarget.DNSName != "lb.example.com." {
+		t.Errorf("AWS CLI output not read")
+	}
+	if _, err = ApexName(out); err == nil {
+		t.Error("Snapshot lacking SOA should fail to tell apex")
+	}
+}
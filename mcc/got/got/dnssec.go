@@ -0,0 +1,250 @@
+package got
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/miekg/dns"
+)
+
+// GetDNSSEC returns the DNSSEC signing status and key signing keys of the
+// hosted zone identified by zoneID.
+func GetDNSSEC(
+	zoneID string,
+	svc route53iface.Route53API,
+) (*route53.GetDNSSECOutput, error) {
+	return svc.GetDNSSEC(&route53.GetDNSSECInput{
+		HostedZoneId: aws.String(zoneID),
+	})
+}
+
+// EnableDNSSEC enables DNSSEC signing of the hosted zone identified by
+// zoneID, creating an active key signing key called name, backed by the
+// KMS key kmsARN, unless the zone has it already.
+func EnableDNSSEC(
+	zoneID string,
+	name string,
+	kmsARN string,
+	svc route53iface.Route53API,
+) (changeInfo *route53.ChangeInfo, err error) {
+	status, err := GetDNSSEC(zoneID, svc)
+	if err != nil {
+		return
+	}
+	found := false
+	for _, key := range status.KeySigningKeys {
+		found = found || aws.StringValue(key.Name) == name
+	}
+	if !found {
+		var res *route53.CreateKeySigningKeyOutput
+		res, err = svc.CreateKeySigningKey(&route53.CreateKeySigningKeyInput{
+			CallerReference:         aws.String(time.Now().Format(time.RFC3339Nano)),
+			HostedZoneId:            aws.String(zoneID),
+			KeyManagementServiceArn: aws.String(kmsARN),
+			Name:                    aws.String(name),
+			Status:                  aws.String("ACTIVE"),
+		})
+		if err != nil {
+			return
+		}
+		WaitForChangeToComplete(res.ChangeInfo, svc)
+	}
+	res, err := svc.EnableHostedZoneDNSSEC(&route53.EnableHostedZoneDNSSECInput{
+		HostedZoneId: aws.String(zoneID),
+	})
+	if err != nil {
+		return
+	}
+	return res.ChangeInfo, nil
+}
+
+// DisableDNSSEC disables DNSSEC signing of the hosted zone identified by
+// zoneID. Its key signing keys are kept, so signing can be enabled again
+// with the same DS record, unless deleteKeys is set, in which case they're
+// deactivated and deleted afterwards, which can't be undone. The DS record
+// must be removed from the parent zone first, or the zone will fail to
+// validate.
+func DisableDNSSEC(
+	zoneID string,
+	deleteKeys bool,
+	svc route53iface.Route53API,
+) (changeInfo *route53.ChangeInfo, err error) {
+	res, err := svc.DisableHostedZoneDNSSEC(&route53.DisableHostedZoneDNSSECInput{
+		HostedZoneId: aws.String(zoneID),
+	})
+	if err != nil {
+		return
+	}
+	changeInfo = res.ChangeInfo
+	if !deleteKeys {
+		return
+	}
+	status, err := GetDNSSEC(zoneID, svc)
+	if err != nil {
+		return
+	}
+	for _, key := range status.KeySigningKeys {
+		WaitForChangeToComplete(changeInfo, svc)
+		if aws.StringValue(key.Status) == "ACTIVE" {
+			var deactivated *route53.DeactivateKeySigningKeyOutput
+			deactivated, err = svc.DeactivateKeySigningKey(
+				&route53.DeactivateKeySigningKeyInput{
+					HostedZoneId: aws.String(zoneID),
+					Name:         key.Name,
+				},
+			)
+			if err != nil {
+				return
+			}
+			WaitForChangeToComplete(deactivated.ChangeInfo, svc)
+		}
+		var deleted *route53.DeleteKeySigningKeyOutput
+		deleted, err = svc.DeleteKeySigningKey(&route53.DeleteKeySigningKeyInput{
+			HostedZoneId: aws.String(zoneID),
+			Name:         key.Name,
+		})
+		if err != nil {
+			return
+		}
+		changeInfo = deleted.ChangeInfo
+	}
+	return
+}
+
+// DSRecords returns the DS records of the active key signing keys in
+// status, to be added to the parent zone.
+func DSRecords(status *route53.GetDNSSECOutput) (records []string) {
+	for _, key := range status.KeySigningKeys {
+		if aws.StringValue(key.Status) == "ACTIVE" && key.DSRecord != nil {
+			records = append(records, *key.DSRecord)
+		}
+	}
+	return
+}
+
+// FormatDNSSEC returns the signing status, followed by a line per key
+// signing key with its name, status, key tag and DS record.
+func FormatDNSSEC(status *route53.GetDNSSECOutput) (output string) {
+	if status.Status != nil {
+		output = aws.StringValue(status.Status.ServeSignature)
+		if status.Status.StatusMessage != nil {
+			output += ": " + *status.Status.StatusMessage
+		}
+		output += "\n"
+	}
+	for _, key := range status.KeySigningKeys {
+		output += fmt.Sprintf(
+			"%s\t%s\t%d\t%s\n",
+			aws.StringValue(key.Name),
+			aws.StringValue(key.Status),
+			aws.Int64Value(key.KeyTag),
+			aws.StringValue(key.DSRecord),
+		)
+	}
+	return
+}
+
+// KeyDSRecords returns the SHA-256 DS records of the key signing keys in
+// the apex DNSKEY record of list, for zones not hosted in Route53.
+func KeyDSRecords(
+	list []*route53.ResourceRecordSet,
+	apex string,
+) (records []string) {
+	for _, value := range RecordValues(list, apex, "DNSKEY") {
+		rr, err := dns.NewRR(
+			fmt.Sprintf("%s IN DNSKEY %s", dns.Fqdn(apex), value),
+		)
+		if err != nil {
+			continue
+		}
+		key, ok := rr.(*dns.DNSKEY)
+		if !ok || key.Flags&dns.SEP == 0 {
+			continue
+		}
+		if ds := key.ToDS(dns.SHA256); ds != nil {
+			records = append(
+				records,
+				strings.TrimPrefix(ds.String(), ds.Header().String()),
+			)
+		}
+	}
+	return
+}
+
+// normalizeDS returns a DS record value comparable regardless of case and
+// spacing.
+func normalizeDS(value string) string {
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
+
+// CheckDS returns the problems found comparing the DS records expected
+// for the zone named apex with the ones its parent publishes.
+func CheckDS(apex string, expected, published []string) (problems []string) {
+	switch {
+	case len(expected) == 0 && len(published) == 0:
+		return
+	case len(expected) == 0:
+		return []string{fmt.Sprintf(
+			"%s: parent publishes DS records but the zone isn't signed, "+
+				"validating resolvers will fail to resolve it",
+			apex,
+		)}
+	case len(published) == 0:
+		return []string{fmt.Sprintf(
+			"%s: zone is signed but its parent publishes no DS record, "+
+				"add %s",
+			apex,
+			strings.Join(expected, ", "),
+		)}
+	}
+	keys := map[string]bool{}
+	for _, value := range expected {
+		keys[normalizeDS(value)] = true
+	}
+	matched := false
+	for _, value := range published {
+		value = normalizeDS(value)
+		if keys[value] {
+			matched = true
+			continue
+		}
+		problems = append(problems, fmt.Sprintf(
+			"%s: parent DS record %s matches no key signing key",
+			apex,
+			value,
+		))
+	}
+	if !matched {
+		problems = append(problems, fmt.Sprintf(
+			"%s: no parent DS record matches a key signing key, add %s",
+			apex,
+			strings.Join(expected, ", "),
+		))
+	}
+	return
+}
+
+// LookupDS returns the DS records the parent of name publishes, asking
+// the nameservers of the zone its parent name is in, as found following
+// referrals from servers.
+func LookupDS(
+	name string,
+	servers []string,
+	resolver Resolver,
+) (values []string, err error) {
+	parents, _, err := lookupReferrals(ParentName(name), servers, resolver)
+	if err != nil {
+		return
+	}
+	for _, ns := range parents {
+		values, err = resolver.Query(ns, name, route53.RRTypeDs)
+		if err == nil {
+			return
+		}
+	}
+	return
+}
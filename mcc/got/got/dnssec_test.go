@@ -0,0 +1,211 @@
+package got
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+)
+
+// mockDNSSECClient mocks Route53 DNSSEC management, recording the calls
+// made to it.
+type mockDNSSECClient struct {
+	route53iface.Route53API
+	keys  []*route53.KeySigningKey
+	calls []string
+}
+
+var insync = &route53.ChangeInfo{
+	Id:     aws.String("C1"),
+	Status: aws.String(route53.ChangeStatusInsync),
+}
+
+func (m *mockDNSSECClient) GetChange(
+	*route53.GetChangeInput,
+) (*route53.GetChangeOutput, error) {
+	return &route53.GetChangeOutput{ChangeInfo: insync}, nil
+}
+
+func (m *mockDNSSECClient) GetDNSSEC(
+	*route53.GetDNSSECInput,
+) (*route53.GetDNSSECOutput, error) {
+	return &route53.GetDNSSECOutput{
+		Status:         &route53.DNSSECStatus{ServeSignature: aws.String("SIGNING")},
+		KeySigningKeys: m.keys,
+	}, nil
+}
+
+func (m *mockDNSSECClient) CreateKeySigningKey(
+	params *route53.CreateKeySigningKeyInput,
+) (*route53.CreateKeySigningKeyOutput, error) {
+	m.calls = append(m.calls, "create "+*params.Name)
+	return &route53.CreateKeySigningKeyOutput{ChangeInfo: insync}, nil
+}
+
+func (m *mockDNSSECClient) EnableHostedZoneDNSSEC(
+	*route53.EnableHostedZoneDNSSECInput,
+) (*route53.EnableHostedZoneDNSSECOutput, error) {
+	m.calls = append(m.calls, "enable")
+	return &route53.EnableHostedZoneDNSSECOutput{ChangeInfo: insync}, nil
+}
+
+func (m *mockDNSSECClient) DisableHostedZoneDNSSEC(
+	*route53.DisableHostedZoneDNSSECInput,
+) (*route53.DisableHostedZoneDNSSECOutput, error) {
+	m.calls = append(m.calls, "disable")
+	return &route53.DisableHostedZoneDNSSECOutput{ChangeInfo: insync}, nil
+}
+
+func (m *mockDNSSECClient) DeactivateKeySigningKey(
+	params *route53.DeactivateKeySigningKeyInput,
+) (*route53.DeactivateKeySigningKeyOutput, error) {
+	m.calls = append(m.calls, "deactivate "+*params.Name)
+	return &route53.DeactivateKeySigningKeyOutput{ChangeInfo: insync}, nil
+}
+
+func (m *mockDNSSECClient) DeleteKeySigningKey(
+	params *route53.DeleteKeySigningKeyInput,
+) (*route53.DeleteKeySigningKeyOutput, error) {
+	m.calls = append(m.calls, "delete "+*params.Name)
+	return &route53.DeleteKeySigningKeyOutput{ChangeInfo: insync}, nil
+}
+
+var ksk = &route53.KeySigningKey{
+	Name:     aws.String("got"),
+	Status:   aws.String("ACTIVE"),
+	KeyTag:   aws.Int64(12345),
+	DSRecord: aws.String("12345 13 2 ABCDEF"),
+}
+
+var enablednssectests = []struct {
+	name  string
+	keys  []*route53.KeySigningKey
+	calls []string
+}{
+	{"new key", nil, []string{"create got", "enable"}},
+	{"existing key", []*route53.KeySigningKey{ksk}, []string{"enable"}},
+}
+
+func TestEnableDNSSEC(t *testing.T) {
+	for _, tt := range enablednssectests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockDNSSECClient{keys: tt.keys}
+			if _, err := EnableDNSSEC("Z1", "got", "arn:kms", svc); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(svc.calls, tt.calls) {
+				t.Errorf("Expected calls %v, got %v", tt.calls, svc.calls)
+			}
+		})
+	}
+}
+
+func TestDisableDNSSEC(t *testing.T) {
+	inactive := &route53.KeySigningKey{
+		Name:   aws.String("old"),
+		Status: aws.String("INACTIVE"),
+	}
+	svc := &mockDNSSECClient{keys: []*route53.KeySigningKey{ksk, inactive}}
+	if _, err := DisableDNSSEC("Z1", false, svc); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"disable"}; !reflect.DeepEqual(svc.calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, svc.calls)
+	}
+	svc = &mockDNSSECClient{keys: []*route53.KeySigningKey{ksk, inactive}}
+	if _, err := DisableDNSSEC("Z1", true, svc); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"disable", "deactivate got", "delete got", "delete old"}
+	if !reflect.DeepEqual(svc.calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, svc.calls)
+	}
+}
+
+func TestFormatDNSSEC(t *testing.T) {
+	status, _ := (&mockDNSSECClient{keys: []*route53.KeySigningKey{ksk}}).GetDNSSEC(nil)
+	expected := "SIGNING\ngot\tACTIVE\t12345\t12345 13 2 ABCDEF\n"
+	if out := FormatDNSSEC(status); out != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out)
+	}
+	if ds := DSRecords(status); !reflect.DeepEqual(ds, []string{"12345 13 2 ABCDEF"}) {
+		t.Errorf("Unexpected DS records %v", ds)
+	}
+}
+
+var checkdstests = []struct {
+	name      string
+	expected  []string
+	published []string
+	problems  int
+}{
+	{"unsigned", nil, nil, 0},
+	{"matching", []string{"12345 13 2 ABCDEF"}, []string{"12345 13 2 abcdef"}, 0},
+	{"missing", []string{"12345 13 2 ABCDEF"}, nil, 1},
+	{"unsigned with DS", nil, []string{"12345 13 2 abcdef"}, 1},
+	{"stale", []string{"12345 13 2 ABCDEF"}, []string{"12345 13 2 abcdef", "54321 13 2 fedcba"}, 1},
+	{"mismatched", []string{"12345 13 2 ABCDEF"}, []string{"54321 13 2 fedcba"}, 2},
+}
+
+func TestCheckDS(t *testing.T) {
+	for _, tt := range checkdstests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := CheckDS("example.com.", tt.expected, tt.published)
+			if len(problems) != tt.problems {
+				t.Errorf("Expected %d problems, got %q", tt.problems, problems)
+			}
+		})
+	}
+}
+
+func TestKeyDSRecords(t *testing.T) {
+	list := []*route53.ResourceRecordSet{
+		newRecordSet(
+			"example.com.",
+			"DNSKEY",
+			3600,
+			"257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==",
+			"256 3 13 oJMRESz5E4gYzS/q6XDrvU1qMPYIjCWzJaOau8XNEZeqCYKD5ar0IRd8KqXXFJkqmVfRvMGPmM1x8fGAa2XhSA==",
+		),
+	}
+	records := KeyDSRecords(list, "example.com.")
+	if len(records) != 1 {
+		t.Fatalf("Expected a DS record for the key signing key, got %v", records)
+	}
+	if problems := CheckDS("example.com.", records, records); len(problems) != 0 {
+		t.Errorf("Unexpected problems %v", problems)
+	}
+}
+
+func TestLookupDS(t *testing.T) {
+	resolver := &mockResolver{answers: map[string]map[string][]string{
+		"root": {"com. NS": {"tld."}},
+		"tld.": {"example.com. DS": {"12345 13 2 ABCDEF"}},
+	}}
+	values, err := LookupDS("example.com.", []string{"root"}, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(values, []string{"12345 13 2 ABCDEF"}) {
+		t.Errorf("Unexpected DS records %v", values)
+	}
+}
+
+func TestLookupDSSkipsLabels(t *testing.T) {
+	resolver := &mockResolver{answers: map[string]map[string][]string{
+		"root": {"com. NS": {"tld."}},
+		"tld.": {"example.com. NS": {"ns1.example.net."}},
+		"ns1.example.net.": {
+			"dev.internal.example.com. DS": {"12345 13 2 ABCDEF"},
+		},
+	}}
+	values, err := LookupDS("dev.internal.example.com.", []string{"root"}, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(values, []string{"12345 13 2 ABCDEF"}) {
+		t.Errorf("Unexpected DS records %v", values)
+	}
+}
@@ -18,6 +18,9 @@ var Verbose bool
 // Dryrun flag
 var Dryrun bool
 
+// Duration specifies time to wait in between checks for a change.
+var Duration = time.Duration(5) * time.Second
+
 // Filter type for generic filtering
 type Filter []string
 
@@ -143,7 +146,7 @@ func DeleteChangeList(
 // WaitForChangeToComplete waits until the ChangeInfo described by the argument is completed.
 func WaitForChangeToComplete(
 	changeInfo *route53.ChangeInfo,
-	svc *route53.Route53,
+	svc route53iface.Route53API,
 ) {
 	getChangeInput := route53.GetChangeInput{Id: changeInfo.Id}
 	getChangeOutput, err := svc.GetChange(&getChangeInput)
@@ -151,7 +154,8 @@ func WaitForChangeToComplete(
 		log.Panic(err.Error())
 	}
 	for *getChangeOutput.ChangeInfo.Status != route53.ChangeStatusInsync {
-		time.Sleep(1)
+		// This is to avoid AWS API rate throttling.
+		time.Sleep(Duration)
 		getChangeOutput, err = svc.GetChange(&getChangeInput)
 		if err != nil {
 			log.Panic(err.Error())
@@ -177,13 +181,7 @@ func UpsertResourceRecordSetTTL(
 	if len(list) <= 0 {
 		log.Fatal("No records to process.")
 	}
-	changeSlice := []*route53.Change{}
-	for _, r := range list {
-		partialChangeSlice := UpsertChangeList(r.ResourceRecords, ttl, *r.Name, *r.Type)
-		changeSlice = append(changeSlice, partialChangeSlice...)
-	}
-
-	changeResponse, err = ApplyChanges(changeSlice, zoneID, svc)
+	changeResponse, err = ApplyChanges(TTLChangeList(list, ttl), zoneID, svc)
 	return
 }
 
@@ -262,20 +260,13 @@ func FilterResourceRecords(
 }
 
 // GetZoneID returns a string containing the ZoneID for use in further API
-// actions
+// actions. Only a zone named exactly zoneName is accepted.
 func GetZoneID(zoneName string, svc route53iface.Route53API) (zoneID string) {
-	params := &route53.ListHostedZonesByNameInput{
-		DNSName:  aws.String(zoneName),
-		MaxItems: aws.String("100"),
-	}
-	resp, err := svc.ListHostedZonesByName(params)
+	zone, err := FindHostedZone(ZoneSelector{Name: zoneName}, svc)
 	if err != nil {
-		log.Println(err.Error())
-	}
-	if len(resp.HostedZones) == 0 {
-		log.Fatalf("No results for zone %s. Exiting.\n", zoneName)
+		log.Fatalf("%s. Exiting.\n", err)
 	}
-	zoneID = *resp.HostedZones[0].Id
+	zoneID = *zone.Id
 	if Verbose {
 		// Pretty-print the response data.
 		fmt.Println(zoneID)
@@ -12,6 +12,8 @@ import (
 var one = "one.example.com."
 var two = "two.example.com."
 var awsCname = "ec2-1-2-3-4.compute-1.amazonaws.com"
+var nsOne = "ns-1.awsdns-01.org"
+var nsTwo = "ns-2.awsdns-02.net"
 var hundred = "100"
 var zoneName = "test"
 var A = "A"
@@ -71,6 +73,20 @@ func (m *mockRoute53Client) ListHostedZonesByName(
 	return
 }
 
+func (m *mockRoute53Client) GetHostedZone(
+	params *route53.GetHostedZoneInput,
+) (out *route53.GetHostedZoneOutput, err error) {
+	hostedZone.Id = params.Id
+	hostedZone.Name = &zoneName
+	out = &route53.GetHostedZoneOutput{
+		HostedZone: &hostedZone,
+		DelegationSet: &route53.DelegationSet{
+			NameServers: []*string{&nsOne, &nsTwo},
+		},
+	}
+	return
+}
+
 func (m *mockRoute53Client) ChangeResourceRecordSets(
 	params *route53.ChangeResourceRecordSetsInput,
 ) (out *route53.ChangeResourceRecordSetsOutput, err error) {
@@ -0,0 +1,192 @@
+package got
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+)
+
+// HealthCheckParams represents health check parameters. Zero values are
+// left unset.
+type HealthCheckParams struct {
+	Type             string
+	FQDN             string
+	IPAddress        string
+	Port             int64
+	Path             string
+	Interval         int64
+	FailureThreshold int64
+}
+
+// GetCreateInput method creates a new CreateHealthCheckInput from the
+// params.
+func (params HealthCheckParams) GetCreateInput() *route53.CreateHealthCheckInput {
+	config := &route53.HealthCheckConfig{
+		Type: aws.String(strings.ToUpper(params.Type)),
+	}
+	if params.FQDN != "" {
+		config.FullyQualifiedDomainName = aws.String(params.FQDN)
+	}
+	if params.IPAddress != "" {
+		config.IPAddress = aws.String(params.IPAddress)
+	}
+	if params.Port != 0 {
+		config.Port = aws.Int64(params.Port)
+	}
+	if params.Path != "" {
+		config.ResourcePath = aws.String(params.Path)
+	}
+	if params.Interval != 0 {
+		config.RequestInterval = aws.Int64(params.Interval)
+	}
+	if params.FailureThreshold != 0 {
+		config.FailureThreshold = aws.Int64(params.FailureThreshold)
+	}
+	return &route53.CreateHealthCheckInput{
+		CallerReference:   aws.String(time.Now().Format(time.RFC3339Nano)),
+		HealthCheckConfig: config,
+	}
+}
+
+// GetUpdateInput method creates a new UpdateHealthCheckInput from the
+// params. Type and interval can't be updated.
+func (params HealthCheckParams) GetUpdateInput(
+	id string,
+) *route53.UpdateHealthCheckInput {
+	input := &route53.UpdateHealthCheckInput{
+		HealthCheckId: aws.String(id),
+	}
+	if params.FQDN != "" {
+		input.FullyQualifiedDomainName = aws.String(params.FQDN)
+	}
+	if params.IPAddress != "" {
+		input.IPAddress = aws.String(params.IPAddress)
+	}
+	if params.Port != 0 {
+		input.Port = aws.Int64(params.Port)
+	}
+	if params.Path != "" {
+		input.ResourcePath = aws.String(params.Path)
+	}
+	if params.FailureThreshold != 0 {
+		input.FailureThreshold = aws.Int64(params.FailureThreshold)
+	}
+	return input
+}
+
+// CreateHealthCheck creates a new health check and returns its ID.
+func CreateHealthCheck(
+	params HealthCheckParams,
+	svc route53iface.Route53API,
+) (id string, err error) {
+	input := params.GetCreateInput()
+	if err = input.Validate(); err != nil {
+		err = fmt.Errorf("Health check parameters failed to validate: %s", err)
+		return
+	}
+	res, err := svc.CreateHealthCheck(input)
+	if err != nil {
+		return
+	}
+	return *res.HealthCheck.Id, nil
+}
+
+// UpdateHealthCheck updates the health check identified by id.
+func UpdateHealthCheck(
+	id string,
+	params HealthCheckParams,
+	svc route53iface.Route53API,
+) (err error) {
+	input := params.GetUpdateInput(id)
+	if err = input.Validate(); err != nil {
+		err = fmt.Errorf("Health check parameters failed to validate: %s", err)
+		return
+	}
+	_, err = svc.UpdateHealthCheck(input)
+	return
+}
+
+// DeleteHealthCheck deletes the health check identified by id.
+func DeleteHealthCheck(id string, svc route53iface.Route53API) (err error) {
+	_, err = svc.DeleteHealthCheck(&route53.DeleteHealthCheckInput{
+		HealthCheckId: aws.String(id),
+	})
+	return
+}
+
+// ListHealthChecks returns all the health checks in the account. It may
+// issue more than one request as each returns a fixed amount of entries
+// at most.
+func ListHealthChecks(
+	svc route53iface.Route53API,
+) (checks []*route53.HealthCheck, err error) {
+	params := &route53.ListHealthChecksInput{}
+	for respIsTruncated := true; respIsTruncated; {
+		var resp *route53.ListHealthChecksOutput
+		resp, err = svc.ListHealthChecks(params)
+		if err != nil {
+			return
+		}
+		params.Marker = resp.NextMarker
+		respIsTruncated = aws.BoolValue(resp.IsTruncated)
+		checks = append(checks, resp.HealthChecks...)
+	}
+	return
+}
+
+// FormatHealthChecks returns a line per health check with its ID, type,
+// endpoint, interval and failure threshold.
+func FormatHealthChecks(checks []*route53.HealthCheck) (output string) {
+	for _, check := range checks {
+		config := check.HealthCheckConfig
+		endpoint := aws.StringValue(config.FullyQualifiedDomainName)
+		if config.IPAddress != nil {
+			endpoint = *config.IPAddress
+		}
+		if config.Port != nil {
+			endpoint += fmt.Sprintf(":%d", *config.Port)
+		}
+		endpoint += aws.StringValue(config.ResourcePath)
+		output += fmt.Sprintf(
+			"%s\t%s\t%s\t%ds\t%d\n",
+			*check.Id,
+			*config.Type,
+			endpoint,
+			aws.Int64Value(config.RequestInterval),
+			aws.Int64Value(config.FailureThreshold),
+		)
+	}
+	return
+}
+
+// RecordSetOptions holds the routing settings of record sets. Zero values
+// are left unset.
+type RecordSetOptions struct {
+	SetIdentifier string
+	Failover      string
+	Weight        *int64
+	HealthCheckID string
+}
+
+// Apply sets the options on every record set in changes.
+func (o RecordSetOptions) Apply(changes []*route53.Change) {
+	for _, change := range changes {
+		set := change.ResourceRecordSet
+		if o.SetIdentifier != "" {
+			set.SetIdentifier = aws.String(o.SetIdentifier)
+		}
+		if o.Failover != "" {
+			set.Failover = aws.String(strings.ToUpper(o.Failover))
+		}
+		if o.Weight != nil {
+			set.Weight = o.Weight
+		}
+		if o.HealthCheckID != "" {
+			set.HealthCheckId = aws.String(o.HealthCheckID)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package got
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+func TestHealthCheckParams(t *testing.T) {
+	params := HealthCheckParams{
+		Type:             "https",
+		FQDN:             "www.example.com",
+		Path:             "/health",
+		Interval:         10,
+		FailureThreshold: 3,
+	}
+	create := params.GetCreateInput()
+	if err := create.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	config := create.HealthCheckConfig
+	if *config.Type != "HTTPS" || *config.ResourcePath != "/health" ||
+		*config.RequestInterval != 10 || *config.FailureThreshold != 3 {
+		t.Errorf("Unexpected health check config %v", config)
+	}
+	if config.Port != nil || config.IPAddress != nil {
+		t.Errorf("Unset params should not be set %v", config)
+	}
+	update := HealthCheckParams{Path: "/status"}.GetUpdateInput("hc1")
+	if err := update.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if *update.HealthCheckId != "hc1" || *update.ResourcePath != "/status" ||
+		update.FullyQualifiedDomainName != nil {
+		t.Errorf("Unexpected health check update %v", update)
+	}
+}
+
+func TestFormatHealthChecks(t *testing.T) {
+	checks := []*route53.HealthCheck{
+		{
+			Id: aws.String("hc1"),
+			HealthCheckConfig: &route53.HealthCheckConfig{
+				Type:                     aws.String("HTTP"),
+				FullyQualifiedDomainName: aws.String("www.example.com"),
+				Port:                     aws.Int64(80),
+				ResourcePath:             aws.String("/health"),
+				RequestInterval:          aws.Int64(30),
+				FailureThreshold:         aws.Int64(3),
+			},
+		},
+		{
+			Id: aws.String("hc2"),
+			HealthCheckConfig: &route53.HealthCheckConfig{
+				Type:             aws.String("TCP"),
+				IPAddress:        aws.String("10.0.0.1"),
+				Port:             aws.Int64(5432),
+				RequestInterval:  aws.Int64(10),
+				FailureThreshold: aws.Int64(2),
+			},
+		},
+	}
+	expected := "hc1\tHTTP\twww.example.com:80/health\t30s\t3\n" +
+		"hc2\tTCP\t10.0.0.1:5432\t10s\t2\n"
+	if out := FormatHealthChecks(checks); out != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out)
+	}
+}
+
+func TestRecordSetOptions(t *testing.T) {
+	changes := UpsertChangeList(
+		NewResourceRecordList([]string{"10.0.0.1"}),
+		60,
+		"www.example.com.",
+		"A",
+	)
+	RecordSetOptions{
+		SetIdentifier: "primary",
+		Failover:      "primary",
+		HealthCheckID: "hc1",
+	}.Apply(changes)
+	set := changes[0].ResourceRecordSet
+	if *set.SetIdentifier != "primary" || *set.Failover != "PRIMARY" ||
+		*set.HealthCheckId != "hc1" || set.Weight != nil {
+		t.Errorf("Unexpected record set %v", set)
+	}
+	expected := "UPSERT www A [primary] 60 failover=PRIMARY healthcheck=hc1 10.0.0.1\n"
+	if out := DescribeChanges(changes, "example.com"); out != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out)
+	}
+}
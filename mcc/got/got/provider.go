@@ -0,0 +1,103 @@
+package got
+
+import (
+	"log"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+)
+
+// Provider is a DNS service backend managing the records of a zone.
+// Records and changes are represented with Route53 types regardless of
+// the backend.
+type Provider interface {
+	// Zone returns the fully qualified name of the zone.
+	Zone() string
+	// List returns all record sets in the zone.
+	List() ([]*route53.ResourceRecordSet, error)
+	// Apply submits UPSERT and DELETE changes as a single batch, and
+	// returns an identifier to Wait for them.
+	Apply(changes []*route53.Change) (string, error)
+	// Wait waits until the changes identified by id are applied.
+	Wait(id string) error
+	// NameServers returns the authoritative nameservers of the zone.
+	NameServers() ([]string, error)
+}
+
+// Route53Provider is a Provider for a Route53 hosted zone.
+type Route53Provider struct {
+	ZoneID   string
+	ZoneName string
+	Svc      route53iface.Route53API
+}
+
+// NewRoute53Provider creates a Route53Provider for zone.
+func NewRoute53Provider(
+	zone *route53.HostedZone,
+	svc route53iface.Route53API,
+) *Route53Provider {
+	return &Route53Provider{
+		ZoneID:   *zone.Id,
+		ZoneName: *zone.Name,
+		Svc:      svc,
+	}
+}
+
+// Zone returns the name of the hosted zone.
+func (p *Route53Provider) Zone() string {
+	return p.ZoneName
+}
+
+// List returns all record sets in the hosted zone.
+func (p *Route53Provider) List() ([]*route53.ResourceRecordSet, error) {
+	return GetResourceRecordSet(p.ZoneID, p.Svc), nil
+}
+
+// Apply submits changes to the hosted zone and returns the change ID.
+func (p *Route53Provider) Apply(changes []*route53.Change) (string, error) {
+	res, err := ApplyChanges(changes, &p.ZoneID, p.Svc)
+	if err != nil || res == nil {
+		return "", err
+	}
+	log.Println(res)
+	return *res.ChangeInfo.Id, nil
+}
+
+// Wait waits until the change identified by id is INSYNC.
+func (p *Route53Provider) Wait(id string) error {
+	if id != "" {
+		WaitForChangeToComplete(&route53.ChangeInfo{Id: aws.String(id)}, p.Svc)
+	}
+	return nil
+}
+
+// NameServers returns the nameservers of the hosted zone.
+func (p *Route53Provider) NameServers() ([]string, error) {
+	return GetNameServers(p.ZoneID, p.Svc)
+}
+
+// TTLChangeList generates a list of changes for UPSERTing the records in
+// list with a different TTL. Alias records are skipped, as they have none.
+func TTLChangeList(
+	list []*route53.ResourceRecordSet,
+	ttl int64,
+) (res []*route53.Change) {
+	for _, r := range list {
+		if r.AliasTarget != nil {
+			continue
+		}
+		set := *r
+		set.TTL = aws.Int64(ttl)
+		log.Printf(
+			"Adding %s to change list for TTL %d\n",
+			*set.Name,
+			ttl,
+		)
+		res = append(res, &route53.Change{
+			Action:            aws.String(route53.ChangeActionUpsert),
+			ResourceRecordSet: &set,
+		})
+	}
+	return
+}
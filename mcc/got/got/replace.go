@@ -0,0 +1,110 @@
+package got
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// sameTarget returns true if both values point to the same target,
+// regardless of case and trailing dots.
+func sameTarget(a, b string) bool {
+	return strings.EqualFold(
+		strings.TrimSuffix(a, "."),
+		strings.TrimSuffix(b, "."),
+	)
+}
+
+// replaceValue replaces the target of a value of a record of type typ
+// with to, if it is from: the last field of MX and SRV values, and the
+// whole value of A, AAAA, CNAME, NS and PTR ones. Values of other types,
+// such as TXT, are left as they are. It returns false if there was
+// nothing to replace.
+func replaceValue(typ, value, from, to string) (string, bool) {
+	switch typ {
+	case route53.RRTypeMx, route53.RRTypeSrv:
+		fields := strings.Fields(value)
+		if len(fields) == 0 || !sameTarget(fields[len(fields)-1], from) {
+			return value, false
+		}
+		fields[len(fields)-1] = to
+		return strings.Join(fields, " "), true
+	case route53.RRTypeA, route53.RRTypeAaaa, route53.RRTypeCname,
+		route53.RRTypeNs, route53.RRTypePtr:
+		if sameTarget(strings.TrimSpace(value), from) {
+			return to, true
+		}
+	}
+	return value, false
+}
+
+// valueKey returns value normalized to tell duplicates apart, regardless
+// of case, spacing and trailing dots.
+func valueKey(value string) string {
+	return strings.ToLower(
+		strings.TrimSuffix(strings.Join(strings.Fields(value), " "), "."),
+	)
+}
+
+// ReplaceChangeList generates a list of changes for UPSERTing the record
+// sets in list having from as a value or alias target, with to instead.
+// Only record sets of type typ are considered, unless it is empty. Values
+// which become duplicates are only kept once, as Route53 rejects them.
+func ReplaceChangeList(
+	list []*route53.ResourceRecordSet,
+	from string,
+	to string,
+	typ string,
+) (res []*route53.Change) {
+	for _, set := range list {
+		if typ != "" && *set.Type != typ {
+			continue
+		}
+		replaced := false
+		newSet := *set
+		if set.AliasTarget != nil &&
+			sameTarget(*set.AliasTarget.DNSName, from) {
+			alias := *set.AliasTarget
+			alias.DNSName = aws.String(to)
+			newSet.AliasTarget = &alias
+			replaced = true
+		}
+		newSet.ResourceRecords = nil
+		seen := map[string]bool{}
+		for _, record := range set.ResourceRecords {
+			value, ok := replaceValue(*set.Type, *record.Value, from, to)
+			replaced = replaced || ok
+			if seen[valueKey(value)] {
+				continue
+			}
+			seen[valueKey(value)] = true
+			newSet.ResourceRecords = append(
+				newSet.ResourceRecords,
+				&route53.ResourceRecord{Value: aws.String(value)},
+			)
+		}
+		if replaced {
+			res = append(res, &route53.Change{
+				Action:            aws.String(route53.ChangeActionUpsert),
+				ResourceRecordSet: &newSet,
+			})
+		}
+	}
+	return
+}
+
+// DescribeChanges returns a line per change with its action and the
+// resulting record set, relative to apex.
+func DescribeChanges(changes []*route53.Change, apex string) (output string) {
+	for _, change := range changes {
+		output += fmt.Sprintf(
+			"%s %s %s\n",
+			*change.Action,
+			recordSetKey(change.ResourceRecordSet, apex),
+			recordSetData(change.ResourceRecordSet),
+		)
+	}
+	return
+}
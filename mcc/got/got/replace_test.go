@@ -0,0 +1,77 @@
+package got
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+func TestReplaceChangeList(t *testing.T) {
+	alias := &route53.ResourceRecordSet{
+		Name: aws.String("example.com."),
+		Type: aws.String("A"),
+		AliasTarget: &route53.AliasTarget{
+			HostedZoneId: aws.String("Z35SXDOTRQ7X7K"),
+			DNSName:      aws.String("old-lb.elb.amazonaws.com."),
+		},
+	}
+	list := []*route53.ResourceRecordSet{
+		alias,
+		newRecordSet("www.example.com.", "CNAME", 300, "OLD-LB.elb.amazonaws.com"),
+		newRecordSet("mail.example.com.", "MX", 300, "10 old-lb.elb.amazonaws.com.", "20  mx.example.com."),
+		newRecordSet("_sip._tcp.example.com.", "SRV", 300, "0 5 5060 old-lb.elb.amazonaws.com"),
+		newRecordSet("txt.example.com.", "TXT", 300, "\"old-lb.elb.amazonaws.com is old\""),
+		newRecordSet("spf.example.com.", "TXT", 300, "\"v=spf1   include:old-lb.elb.amazonaws.com -all\""),
+		newRecordSet("other.example.com.", "CNAME", 300, "other.example.com"),
+	}
+	changes := ReplaceChangeList(
+		list,
+		"old-lb.elb.amazonaws.com",
+		"new-lb.elb.amazonaws.com",
+		"",
+	)
+	expected := "UPSERT @ A ALIAS new-lb.elb.amazonaws.com\n" +
+		"UPSERT www CNAME 300 new-lb.elb.amazonaws.com\n" +
+		"UPSERT mail MX 300 10 new-lb.elb.amazonaws.com 20  mx.example.com.\n" +
+		"UPSERT _sip._tcp SRV 300 0 5 5060 new-lb.elb.amazonaws.com\n"
+	if out := DescribeChanges(changes, "example.com."); out != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out)
+	}
+	if *alias.AliasTarget.DNSName != "old-lb.elb.amazonaws.com." {
+		t.Error("Original record sets must not be modified")
+	}
+	changes = ReplaceChangeList(
+		[]*route53.ResourceRecordSet{
+			newRecordSet("api.example.com.", "A", 300, "10.0.0.1", "10.0.0.2", "10.0.0.3"),
+		},
+		"10.0.0.1",
+		"10.0.0.2",
+		"",
+	)
+	expected = "UPSERT api A 300 10.0.0.2 10.0.0.3\n"
+	if out := DescribeChanges(changes, "example.com."); out != expected {
+		t.Errorf("Expected duplicates dropped:\n%s\nGot:\n%s", expected, out)
+	}
+	changes = ReplaceChangeList(
+		[]*route53.ResourceRecordSet{
+			newRecordSet("example.com.", "NS", 300, "ns-1.example.net.", "NS-2.example.net"),
+		},
+		"ns-1.example.net",
+		"ns-2.example.net.",
+		"",
+	)
+	expected = "UPSERT @ NS 300 ns-2.example.net.\n"
+	if out := DescribeChanges(changes, "example.com."); out != expected {
+		t.Errorf("Expected duplicates dropped:\n%s\nGot:\n%s", expected, out)
+	}
+	changes = ReplaceChangeList(
+		list,
+		"old-lb.elb.amazonaws.com",
+		"new-lb.elb.amazonaws.com",
+		"CNAME",
+	)
+	if len(changes) != 1 || *changes[0].ResourceRecordSet.Type != "CNAME" {
+		t.Errorf("Only CNAME records should be replaced")
+	}
+}
@@ -0,0 +1,233 @@
+package got
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/miekg/dns"
+)
+
+// TSIGKey holds the key to sign RFC 2136 updates and zone transfers with.
+type TSIGKey struct {
+	Name      string
+	Secret    string
+	Algorithm string
+}
+
+// RFC2136Provider is a Provider for a zone in a DNS server accepting
+// RFC 2136 dynamic updates, such as BIND or Knot. Records are listed
+// through zone transfers, which the server must allow.
+type RFC2136Provider struct {
+	Server   string
+	ZoneName string
+	Key      *TSIGKey
+	Timeout  time.Duration
+}
+
+// NewRFC2136Provider creates an RFC2136Provider for zone in server, which
+// defaults to DNS port if none is specified.
+func NewRFC2136Provider(server, zone string, key *TSIGKey) *RFC2136Provider {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	if key != nil {
+		key.Name = dns.Fqdn(key.Name)
+		key.Algorithm = dns.Fqdn(key.Algorithm)
+	}
+	return &RFC2136Provider{
+		Server:   server,
+		ZoneName: dns.Fqdn(zone),
+		Key:      key,
+		Timeout:  10 * time.Second,
+	}
+}
+
+// Zone returns the name of the zone.
+func (p *RFC2136Provider) Zone() string {
+	return p.ZoneName
+}
+
+// sign signs msg with the provider key, if any.
+func (p *RFC2136Provider) sign(msg *dns.Msg) {
+	if p.Key != nil {
+		msg.SetTsig(p.Key.Name, p.Key.Algorithm, 300, time.Now().Unix())
+	}
+}
+
+// secrets returns the TSIG secrets to sign with, if any.
+func (p *RFC2136Provider) secrets() map[string]string {
+	if p.Key == nil {
+		return nil
+	}
+	return map[string]string{p.Key.Name: p.Key.Secret}
+}
+
+// List returns all record sets in the zone, transferring it from server.
+func (p *RFC2136Provider) List() (list []*route53.ResourceRecordSet, err error) {
+	msg := new(dns.Msg)
+	msg.SetAxfr(p.ZoneName)
+	p.sign(msg)
+	transfer := &dns.Transfer{
+		DialTimeout:  p.Timeout,
+		ReadTimeout:  p.Timeout,
+		WriteTimeout: p.Timeout,
+		TsigSecret:   p.secrets(),
+	}
+	envelopes, err := transfer.In(msg, p.Server)
+	if err != nil {
+		return
+	}
+	records := []dns.RR{}
+	for envelope := range envelopes {
+		if envelope.Error != nil {
+			return nil, envelope.Error
+		}
+		records = append(records, envelope.RR...)
+	}
+	// Transfers start and end with the SOA record
+	if len(records) > 1 {
+		records = records[:len(records)-1]
+	}
+	return NewResourceRecordSets(records), nil
+}
+
+// NewResourceRecordSets groups records by name and type into record
+// sets, keeping the order they were first found in.
+func NewResourceRecordSets(records []dns.RR) (list []*route53.ResourceRecordSet) {
+	sets := map[string]*route53.ResourceRecordSet{}
+	for _, rr := range records {
+		header := rr.Header()
+		typ := dns.TypeToString[header.Rrtype]
+		key := strings.ToLower(header.Name) + " " + typ
+		set, ok := sets[key]
+		if !ok {
+			set = &route53.ResourceRecordSet{
+				Name: aws.String(header.Name),
+				Type: aws.String(typ),
+				TTL:  aws.Int64(int64(header.Ttl)),
+			}
+			sets[key] = set
+			list = append(list, set)
+		}
+		set.ResourceRecords = append(
+			set.ResourceRecords,
+			&route53.ResourceRecord{
+				Value: aws.String(
+					strings.TrimPrefix(rr.String(), header.String()),
+				),
+			},
+		)
+	}
+	return
+}
+
+// newRRs returns the DNS records in a record set.
+func newRRs(set *route53.ResourceRecordSet) (records []dns.RR, err error) {
+	if set.AliasTarget != nil || set.SetIdentifier != nil {
+		err = fmt.Errorf(
+			"Alias and routing policies of %s are not supported by RFC 2136",
+			*set.Name,
+		)
+		return
+	}
+	for _, record := range set.ResourceRecords {
+		var rr dns.RR
+		rr, err = dns.NewRR(fmt.Sprintf(
+			"%s %d IN %s %s",
+			dns.Fqdn(*set.Name),
+			aws.Int64Value(set.TTL),
+			*set.Type,
+			*record.Value,
+		))
+		if err != nil {
+			return
+		}
+		records = append(records, rr)
+	}
+	return
+}
+
+// NewUpdate creates an RFC 2136 update message for zone with changes.
+// UPSERTs replace the whole record set, and DELETEs remove its records.
+func NewUpdate(zone string, changes []*route53.Change) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetUpdate(dns.Fqdn(zone))
+	for _, change := range changes {
+		records, err := newRRs(change.ResourceRecordSet)
+		if err != nil {
+			return nil, err
+		}
+		switch *change.Action {
+		case route53.ChangeActionUpsert:
+			msg.RemoveRRset(records)
+			msg.Insert(records)
+		case route53.ChangeActionDelete:
+			msg.Remove(records)
+		default:
+			return nil, fmt.Errorf(
+				"Action %s is not supported by RFC 2136",
+				*change.Action,
+			)
+		}
+	}
+	return msg, nil
+}
+
+// Apply sends changes to server as a single update. Updates are applied
+// synchronously, so there is nothing to wait for afterwards.
+func (p *RFC2136Provider) Apply(changes []*route53.Change) (string, error) {
+	if len(changes) <= 0 {
+		return "", fmt.Errorf("No records to process")
+	}
+	msg, err := NewUpdate(p.ZoneName, changes)
+	if err != nil {
+		return "", err
+	}
+	if Dryrun {
+		return "", nil
+	}
+	p.sign(msg)
+	client := &dns.Client{
+		Net:        "tcp",
+		Timeout:    p.Timeout,
+		TsigSecret: p.secrets(),
+	}
+	in, _, err := client.Exchange(msg, p.Server)
+	if err != nil {
+		return "", err
+	}
+	if in.Rcode != dns.RcodeSuccess {
+		return "", fmt.Errorf(
+			"Update of %s failed: %s",
+			p.ZoneName,
+			dns.RcodeToString[in.Rcode],
+		)
+	}
+	return "", nil
+}
+
+// Wait returns immediately, as updates are synchronous.
+func (p *RFC2136Provider) Wait(id string) error {
+	return nil
+}
+
+// NameServers returns the nameservers in the zone apex NS record.
+func (p *RFC2136Provider) NameServers() (nameservers []string, err error) {
+	list, err := p.List()
+	if err != nil {
+		return
+	}
+	if set := findRecordSet(list, p.ZoneName, route53.RRTypeNs); set != nil {
+		for _, record := range set.ResourceRecords {
+			nameservers = append(nameservers, *record.Value)
+		}
+	}
+	if len(nameservers) == 0 {
+		err = fmt.Errorf("No nameservers found for zone %s", p.ZoneName)
+	}
+	return
+}
@@ -0,0 +1,278 @@
+package got
+
+import (
+	"net"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/miekg/dns"
+)
+
+var testKey = TSIGKey{
+	Name:      "got",
+	Secret:    "c2VjcmV0c2VjcmV0c2VjcmV0c2VjcmV0",
+	Algorithm: dns.HmacSHA256,
+}
+
+// authServer is an authoritative server for a single zone, accepting
+// updates and transfers signed with testKey.
+type authServer struct {
+	sync.Mutex
+	records []dns.RR
+}
+
+func (s *authServer) update(ns []dns.RR) {
+	s.Lock()
+	defer s.Unlock()
+	for _, rr := range ns {
+		header := rr.Header()
+		kept := []dns.RR{}
+		for _, existing := range s.records {
+			sameSet := existing.Header().Name == header.Name &&
+				existing.Header().Rrtype == header.Rrtype
+			switch header.Class {
+			case dns.ClassANY:
+				// Remove the whole record set
+				if sameSet {
+					continue
+				}
+			case dns.ClassNONE:
+				// Remove a single record
+				copied := dns.Copy(rr)
+				copied.Header().Class = dns.ClassINET
+				copied.Header().Ttl = existing.Header().Ttl
+				if sameSet && dns.IsDuplicate(existing, copied) {
+					continue
+				}
+			}
+			kept = append(kept, existing)
+		}
+		if header.Class == dns.ClassINET {
+			kept = append(kept, rr)
+		}
+		s.records = kept
+	}
+}
+
+func (s *authServer) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(r)
+	switch {
+	case r.IsTsig() == nil || w.TsigStatus() != nil:
+		m.Rcode = dns.RcodeRefused
+	case r.Opcode == dns.OpcodeUpdate:
+		s.update(r.Ns)
+	case r.Question[0].Qtype == dns.TypeAXFR:
+		s.Lock()
+		m.Answer = append(m.Answer, s.records...)
+		m.Answer = append(m.Answer, s.records[0])
+		s.Unlock()
+	}
+	if tsig := r.IsTsig(); tsig != nil {
+		m.SetTsig(tsig.Hdr.Name, tsig.Algorithm, 300, time.Now().Unix())
+	}
+	_ = w.WriteMsg(m)
+}
+
+func startAuthServer(t *testing.T, zone []string) (
+	server *dns.Server,
+	address string,
+) {
+	handler := &authServer{}
+	for _, line := range zone {
+		rr, err := dns.NewRR(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handler.records = append(handler.records, rr)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server = &dns.Server{
+		Listener:   listener,
+		Handler:    handler,
+		TsigSecret: map[string]string{dns.Fqdn(testKey.Name): testKey.Secret},
+		MsgAcceptFunc: func(dns.Header) dns.MsgAcceptAction {
+			return dns.MsgAccept
+		},
+	}
+	go func() { _ = server.ActivateAndServe() }()
+	return server, listener.Addr().String()
+}
+
+var testZone = []string{
+	"example.com. 3600 IN SOA ns1.example.com. admin.example.com. 1 7200 900 1209600 300",
+	"example.com. 3600 IN NS ns1.example.com.",
+	"example.com. 3600 IN NS ns2.example.com.",
+	"www.example.com. 300 IN A 10.0.0.1",
+	"www.example.com. 300 IN A 10.0.0.2",
+	"example.com. 300 IN TXT \"v=spf1 -all\"",
+}
+
+func newTestProvider(address string) *RFC2136Provider {
+	key := testKey
+	return NewRFC2136Provider(address, "example.com", &key)
+}
+
+func sortedValues(set *route53.ResourceRecordSet) (values []string) {
+	for _, record := range set.ResourceRecords {
+		values = append(values, *record.Value)
+	}
+	sort.Strings(values)
+	return
+}
+
+func TestRFC2136ProviderList(t *testing.T) {
+	server, address := startAuthServer(t, testZone)
+	defer server.Shutdown()
+	list, err := newTestProvider(address).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 4 {
+		t.Fatalf("Expected 4 record sets, got %d", len(list))
+	}
+	www := findRecordSet(list, "www.example.com.", "A")
+	if www == nil {
+		t.Fatal("Record set www.example.com. A not found")
+	}
+	if *www.TTL != 300 {
+		t.Errorf("Expected TTL 300, got %d", *www.TTL)
+	}
+	expected := []string{"10.0.0.1", "10.0.0.2"}
+	if values := sortedValues(www); !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+	txt := findRecordSet(list, "example.com.", "TXT")
+	if txt == nil || *txt.ResourceRecords[0].Value != "\"v=spf1 -all\"" {
+		t.Errorf("Unexpected TXT record set %v", txt)
+	}
+}
+
+func TestRFC2136ProviderRefused(t *testing.T) {
+	server, address := startAuthServer(t, testZone)
+	defer server.Shutdown()
+	provider := NewRFC2136Provider(address, "example.com", &TSIGKey{
+		Name:      testKey.Name,
+		Secret:    "d3Jvbmd3cm9uZ3dyb25nd3Jvbmd3cm9uZw==",
+		Algorithm: testKey.Algorithm,
+	})
+	_, err := provider.Apply(UpsertChangeList(
+		NewResourceRecordList([]string{"10.0.0.3"}),
+		300,
+		"www.example.com.",
+		"A",
+	))
+	if err == nil {
+		t.Error("Expected an error updating with a wrong key")
+	}
+}
+
+var applytests = []struct {
+	name     string
+	changes  []*route53.Change
+	expected []string
+}{
+	{
+		name: "Upsert replaces the record set",
+		changes: UpsertChangeList(
+			NewResourceRecordList([]string{"10.0.0.3"}),
+			60,
+			"www.example.com.",
+			"A",
+		),
+		expected: []string{"10.0.0.3"},
+	},
+	{
+		name: "Upsert creates a new record set",
+		changes: UpsertChangeList(
+			NewResourceRecordList([]string{"10.0.0.3"}),
+			60,
+			"new.example.com.",
+			"A",
+		),
+		expected: []string{"10.0.0.3"},
+	},
+	{
+		name: "Delete removes the record set",
+		changes: []*route53.Change{
+			{
+				Action: aws.String(route53.ChangeActionDelete),
+				ResourceRecordSet: &route53.ResourceRecordSet{
+					Name: aws.String("www.example.com."),
+					Type: aws.String("A"),
+					TTL:  aws.Int64(300),
+					ResourceRecords: NewResourceRecordList(
+						[]string{"10.0.0.1", "10.0.0.2"},
+					),
+				},
+			},
+		},
+		expected: nil,
+	},
+}
+
+func TestRFC2136ProviderApply(t *testing.T) {
+	for _, tt := range applytests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, address := startAuthServer(t, testZone)
+			defer server.Shutdown()
+			provider := newTestProvider(address)
+			if _, err := provider.Apply(tt.changes); err != nil {
+				t.Fatal(err)
+			}
+			list, err := provider.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			name := *tt.changes[0].ResourceRecordSet.Name
+			set := findRecordSet(list, name, "A")
+			var values []string
+			if set != nil {
+				values = sortedValues(set)
+			}
+			if !reflect.DeepEqual(values, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, values)
+			}
+		})
+	}
+}
+
+func TestNewUpdateRejectsAliases(t *testing.T) {
+	_, err := NewUpdate("example.com.", []*route53.Change{
+		{
+			Action: aws.String(route53.ChangeActionUpsert),
+			ResourceRecordSet: &route53.ResourceRecordSet{
+				Name: aws.String("www.example.com."),
+				Type: aws.String("A"),
+				AliasTarget: &route53.AliasTarget{
+					DNSName: aws.String("lb.example.com."),
+				},
+			},
+		},
+	})
+	if err == nil {
+		t.Error("Expected an error updating an alias")
+	}
+}
+
+func TestRFC2136ProviderNameServers(t *testing.T) {
+	server, address := startAuthServer(t, testZone)
+	defer server.Shutdown()
+	nameservers, err := newTestProvider(address).NameServers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(nameservers)
+	expected := []string{"ns1.example.com.", "ns2.example.com."}
+	if !reflect.DeepEqual(nameservers, expected) {
+		t.Errorf("Expected %v, got %v", expected, nameservers)
+	}
+}
@@ -0,0 +1,282 @@
+package got
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// Commands accepted to control a running Shift.
+const (
+	ShiftPause  = "pause"
+	ShiftResume = "resume"
+	ShiftAbort  = "abort"
+)
+
+// MaxWeight is the highest weight Route53 accepts for a record set.
+const MaxWeight = 255
+
+// ErrShiftAborted is returned when a Shift is aborted.
+var ErrShiftAborted = fmt.Errorf("Shift aborted, original weights restored")
+
+// Shift moves traffic in between two weighted record sets sharing name
+// and type.
+type Shift struct {
+	From  *route53.ResourceRecordSet
+	To    *route53.ResourceRecordSet
+	total int64
+}
+
+// NewShift returns a Shift from the weighted record set identified by
+// from to the one identified by to, both with the given name and type.
+// Type may be empty if name has no other weighted record sets.
+func NewShift(
+	list []*route53.ResourceRecordSet,
+	name, typ, from, to string,
+) (shift *Shift, err error) {
+	shift = &Shift{}
+	for _, set := range list {
+		if !sameName(*set.Name, name) || set.Weight == nil ||
+			(typ != "" && *set.Type != typ) {
+			continue
+		}
+		switch aws.StringValue(set.SetIdentifier) {
+		case from:
+			shift.From = set
+		case to:
+			shift.To = set
+		}
+	}
+	if shift.From == nil || shift.To == nil {
+		return nil, fmt.Errorf(
+			"Weighted records %s and %s for %s not found",
+			from,
+			to,
+			name,
+		)
+	}
+	if *shift.From.Type != *shift.To.Type {
+		return nil, fmt.Errorf(
+			"Records %s and %s for %s have different types",
+			from,
+			to,
+			name,
+		)
+	}
+	for _, set := range []*route53.ResourceRecordSet{shift.From, shift.To} {
+		if *set.Weight < 0 || *set.Weight > MaxWeight {
+			return nil, fmt.Errorf(
+				"Weight of %s for %s must be in between 0 and %d, got %d",
+				*set.SetIdentifier,
+				name,
+				MaxWeight,
+				*set.Weight,
+			)
+		}
+	}
+	// The whole weight goes to To eventually, so it's capped to the
+	// maximum Route53 accepts
+	shift.total = *shift.From.Weight + *shift.To.Weight
+	if shift.total > MaxWeight {
+		shift.total = MaxWeight
+	}
+	if shift.total == 0 {
+		shift.total = 100
+	}
+	return
+}
+
+// weighted returns a copy of set with a different weight.
+func weighted(set *route53.ResourceRecordSet, weight int64) *route53.Change {
+	newSet := *set
+	newSet.Weight = aws.Int64(weight)
+	return &route53.Change{
+		Action:            aws.String(route53.ChangeActionUpsert),
+		ResourceRecordSet: &newSet,
+	}
+}
+
+// StepChangeList generates a list of changes for sending percent of the
+// traffic to To, and the rest to From, keeping their total weight, up to
+// MaxWeight.
+func (s *Shift) StepChangeList(percent int64) []*route53.Change {
+	to := s.total * percent / 100
+	return []*route53.Change{
+		weighted(s.From, s.total-to),
+		weighted(s.To, to),
+	}
+}
+
+// RevertChangeList generates a list of changes for restoring the weights
+// From and To had originally.
+func (s *Shift) RevertChangeList() []*route53.Change {
+	return []*route53.Change{
+		weighted(s.From, *s.From.Weight),
+		weighted(s.To, *s.To.Weight),
+	}
+}
+
+// ParseSteps returns the percentages in a comma separated list, which
+// must be increasing and in between 0 and 100.
+func ParseSteps(value string) (steps []int64, err error) {
+	previous := int64(-1)
+	for _, field := range strings.Split(value, ",") {
+		var step int64
+		step, err = strconv.ParseInt(strings.TrimSpace(field), 10, 64)
+		if err != nil {
+			return
+		}
+		if step <= previous || step > 100 {
+			err = fmt.Errorf(
+				"Steps must be increasing percentages, got %s",
+				value,
+			)
+			return
+		}
+		steps = append(steps, step)
+		previous = step
+	}
+	return
+}
+
+// Run applies every step with apply, waiting interval in between them.
+// Commands received from control pause or resume the shift, or abort it,
+// which restores the original weights, as does failing to apply a step.
+func (s *Shift) Run(
+	steps []int64,
+	interval time.Duration,
+	apply func([]*route53.Change) error,
+	control <-chan string,
+) error {
+	for _, step := range steps {
+		if step < 0 || step > 100 {
+			return fmt.Errorf("Steps must be percentages, got %d", step)
+		}
+	}
+	for i, step := range steps {
+		if s.aborted(control) {
+			return s.abort(apply, i > 0)
+		}
+		log.Printf(
+			"Sending %d%% of %s to %s\n",
+			step,
+			*s.To.Name,
+			*s.To.SetIdentifier,
+		)
+		if err := apply(s.StepChangeList(step)); err != nil {
+			return s.restore(apply, err)
+		}
+		if i == len(steps)-1 {
+			break
+		}
+		if s.wait(interval, control) {
+			return s.abort(apply, true)
+		}
+	}
+	return nil
+}
+
+// abort restores the original weights with apply, if they were changed.
+func (s *Shift) abort(
+	apply func([]*route53.Change) error,
+	changed bool,
+) error {
+	log.Println("Aborting, restoring original weights")
+	if changed {
+		if err := apply(s.RevertChangeList()); err != nil {
+			return err
+		}
+	}
+	return ErrShiftAborted
+}
+
+// restore restores the original weights with apply after failing to
+// apply a step with err, which may have been applied partially, and
+// returns err along with the outcome.
+func (s *Shift) restore(
+	apply func([]*route53.Change) error,
+	err error,
+) error {
+	log.Printf("%s, restoring original weights\n", err)
+	if revertErr := apply(s.RevertChangeList()); revertErr != nil {
+		return fmt.Errorf(
+			"%s, and restoring the original weights failed: %s",
+			err,
+			revertErr,
+		)
+	}
+	return fmt.Errorf("%s, original weights restored", err)
+}
+
+// aborted handles the commands pending in control before applying a step,
+// waiting for as long as it's paused, and returns true if it was aborted.
+func (s *Shift) aborted(control <-chan string) bool {
+	paused := false
+	for {
+		var command string
+		if paused {
+			command = <-control
+		} else {
+			select {
+			case command = <-control:
+			default:
+				return false
+			}
+		}
+		switch command {
+		case ShiftAbort:
+			return true
+		case ShiftPause:
+			if !paused {
+				log.Println("Paused")
+			}
+			paused = true
+		case ShiftResume:
+			if paused {
+				log.Println("Resumed")
+			}
+			paused = false
+		}
+	}
+}
+
+// wait waits interval, or for as long as it's paused, and returns true if
+// it was aborted meanwhile.
+func (s *Shift) wait(interval time.Duration, control <-chan string) bool {
+	timer := time.NewTimer(interval)
+	defer func() { timer.Stop() }()
+	paused := false
+	for {
+		var timeout <-chan time.Time
+		if !paused {
+			timeout = timer.C
+		}
+		select {
+		case <-timeout:
+			return false
+		case command := <-control:
+			switch command {
+			case ShiftAbort:
+				return true
+			case ShiftPause:
+				if !paused {
+					log.Println("Paused")
+				}
+				paused = true
+			case ShiftResume:
+				if paused {
+					log.Println("Resumed")
+					// Resuming waits for a whole interval again
+					timer.Stop()
+					timer = time.NewTimer(interval)
+				}
+				paused = false
+			}
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package got
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// newWeightedRecordSet returns a weighted record set for api.example.com.
+func newWeightedRecordSet(id string, weight int64) *route53.ResourceRecordSet {
+	set := newRecordSet("api.example.com.", "CNAME", 60, id+".example.com")
+	set.SetIdentifier = aws.String(id)
+	set.Weight = aws.Int64(weight)
+	return set
+}
+
+var weightedList = []*route53.ResourceRecordSet{
+	newWeightedRecordSet("blue", 200),
+	newWeightedRecordSet("green", 0),
+	newRecordSet("www.example.com.", "CNAME", 60, "api.example.com"),
+}
+
+// weights returns weights of From and To in a list of shift changes.
+func weights(changes []*route53.Change) [2]int64 {
+	return [2]int64{
+		*changes[0].ResourceRecordSet.Weight,
+		*changes[1].ResourceRecordSet.Weight,
+	}
+}
+
+func TestNewShift(t *testing.T) {
+	shift, err := NewShift(weightedList, "api.example.com", "", "blue", "green")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := weights(shift.StepChangeList(25)); out != [2]int64{150, 50} {
+		t.Errorf("Unexpected weights %v", out)
+	}
+	if out := weights(shift.RevertChangeList()); out != [2]int64{200, 0} {
+		t.Errorf("Unexpected weights %v", out)
+	}
+	if *weightedList[0].Weight != 200 {
+		t.Error("Original record sets must not be modified")
+	}
+	if _, err = NewShift(weightedList, "api.example.com", "", "blue", "red"); err == nil {
+		t.Error("Missing set identifiers should fail")
+	}
+	if _, err = NewShift(weightedList, "api.example.com", "A", "blue", "green"); err == nil {
+		t.Error("Records of other types should not be found")
+	}
+}
+
+var pstest = []struct {
+	in    string
+	out   []int64
+	fails bool
+}{
+	{"10,25,50,100", []int64{10, 25, 50, 100}, false},
+	{"50, 100", []int64{50, 100}, false},
+	{"50,25", nil, true},
+	{"50,150", nil, true},
+	{"ten", nil, true},
+}
+
+func TestParseSteps(t *testing.T) {
+	for _, tt := range pstest {
+		t.Run(tt.in, func(t *testing.T) {
+			out, err := ParseSteps(tt.in)
+			if (err != nil) != tt.fails {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if !tt.fails && len(out) != len(tt.out) {
+				t.Errorf("Expected %v, got %v", tt.out, out)
+			}
+		})
+	}
+}
+
+func TestShiftRun(t *testing.T) {
+	shift, err := NewShift(weightedList, "api.example.com", "", "blue", "green")
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied := [][2]int64{}
+	steps := make(chan bool, 10)
+	apply := func(changes []*route53.Change) error {
+		applied = append(applied, weights(changes))
+		steps <- true
+		return nil
+	}
+	control := make(chan string)
+	done := make(chan error)
+	go func() {
+		done <- shift.Run(
+			[]int64{10, 50, 100},
+			time.Hour,
+			apply,
+			control,
+		)
+	}()
+	// Commands are sent while waiting after the first step
+	<-steps
+	control <- ShiftPause
+	control <- ShiftResume
+	control <- ShiftAbort
+	if err = <-done; err != ErrShiftAborted {
+		t.Errorf("Expected abort, got %v", err)
+	}
+	expected := [][2]int64{{180, 20}, {200, 0}}
+	if len(applied) != len(expected) ||
+		applied[0] != expected[0] || applied[1] != expected[1] {
+		t.Errorf("Expected %v applied, got %v", expected, applied)
+	}
+	applied = nil
+	err = shift.Run([]int64{10, 50, 100}, time.Millisecond, apply, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 3 || applied[2] != [2]int64{0, 200} {
+		t.Errorf("Unexpected weights applied %v", applied)
+	}
+}
+
+func TestNewShiftMaxWeight(t *testing.T) {
+	list := []*route53.ResourceRecordSet{
+		newWeightedRecordSet("blue", 200),
+		newWeightedRecordSet("green", 100),
+	}
+	shift, err := NewShift(list, "api.example.com", "", "blue", "green")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, step := range []int64{0, 50, 100} {
+		for _, weight := range weights(shift.StepChangeList(step)) {
+			if weight > MaxWeight {
+				t.Errorf("Weight %d at %d%% is over %d", weight, step, MaxWeight)
+			}
+		}
+	}
+	if out := weights(shift.StepChangeList(100)); out != [2]int64{0, MaxWeight} {
+		t.Errorf("Unexpected weights %v", out)
+	}
+	list[1] = newWeightedRecordSet("green", 300)
+	if _, err = NewShift(list, "api.example.com", "", "blue", "green"); err == nil {
+		t.Error("Weights over the maximum should fail")
+	}
+}
+
+func TestShiftRunAbortBeforeStep(t *testing.T) {
+	shift, err := NewShift(weightedList, "api.example.com", "", "blue", "green")
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied := 0
+	apply := func(changes []*route53.Change) error {
+		applied++
+		return nil
+	}
+	control := make(chan string, 1)
+	control <- ShiftAbort
+	if err = shift.Run([]int64{10, 100}, time.Hour, apply, control); err != ErrShiftAborted {
+		t.Errorf("Expected abort, got %v", err)
+	}
+	if applied != 0 {
+		t.Errorf("Expected no changes applied, got %d", applied)
+	}
+	if err = shift.Run([]int64{10, 150}, time.Millisecond, apply, nil); err == nil {
+		t.Error("Steps over 100% should fail")
+	}
+	if applied != 0 {
+		t.Errorf("Expected no changes applied, got %d", applied)
+	}
+}
+
+func TestShiftRunApplyError(t *testing.T) {
+	shift, err := NewShift(weightedList, "api.example.com", "", "blue", "green")
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied := [][]*route53.Change{}
+	apply := func(changes []*route53.Change) error {
+		applied = append(applied, changes)
+		if len(applied) == 2 {
+			return fmt.Errorf("Throttled")
+		}
+		return nil
+	}
+	err = shift.Run([]int64{10, 50, 100}, time.Millisecond, apply, nil)
+	if err == nil || err.Error() != "Throttled, original weights restored" {
+		t.Errorf("Expected the error and the weights restored, got %v", err)
+	}
+	if len(applied) != 3 {
+		t.Fatalf("Expected 2 steps and a revert applied, got %d", len(applied))
+	}
+	for i, change := range shift.RevertChangeList() {
+		if *applied[2][i].ResourceRecordSet.Weight != *change.ResourceRecordSet.Weight {
+			t.Errorf("Expected the original weights restored, got %v", applied[2])
+		}
+	}
+}
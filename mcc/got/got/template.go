@@ -0,0 +1,99 @@
+package got
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// ParseVars returns the variables in a list of name=value assignments.
+func ParseVars(assignments []string) (vars map[string]string, err error) {
+	vars = map[string]string{}
+	for _, assignment := range assignments {
+		parts := strings.SplitN(assignment, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf(
+				"Variables must be assigned as name=value, got %s",
+				assignment,
+			)
+		}
+		vars[parts[0]] = parts[1]
+	}
+	return
+}
+
+// escapeJSON returns value escaped to be used within a JSON string.
+func escapeJSON(value string) string {
+	out, _ := json.Marshal(value)
+	return string(out[1 : len(out)-1])
+}
+
+// RenderTemplate renders a record sets template, a Snapshot in JSON
+// format using text/template syntax, with vars. The zone variable holds
+// apex unless set in vars. Using undefined variables is an error. Values
+// are escaped to be used within JSON strings, so they can't alter the
+// snapshot's structure.
+func RenderTemplate(
+	text string,
+	apex string,
+	vars map[string]string,
+) (list []*route53.ResourceRecordSet, err error) {
+	tmpl, err := template.New("records").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return
+	}
+	data := map[string]string{"zone": strings.TrimSuffix(apex, ".")}
+	for name, value := range vars {
+		data[name] = value
+	}
+	for name, value := range data {
+		data[name] = escapeJSON(value)
+	}
+	out := &bytes.Buffer{}
+	if err = tmpl.Execute(out, data); err != nil {
+		return
+	}
+	list, err = ReadSnapshot(out)
+	if err != nil {
+		err = fmt.Errorf("Rendered template is not a valid snapshot: %s", err)
+	}
+	return
+}
+
+// QualifyName returns name fully qualified, taking names not ending with
+// a dot as relative to apex and "@" as the apex itself.
+func QualifyName(name, apex string) string {
+	apex = strings.TrimSuffix(apex, ".") + "."
+	switch {
+	case name == "@" || name == "":
+		return apex
+	case strings.HasSuffix(name, "."):
+		return name
+	}
+	return name + "." + apex
+}
+
+// TemplateChangeList generates a list of changes for UPSERTing the record
+// sets rendered from a template into the zone named apex.
+func TemplateChangeList(
+	list []*route53.ResourceRecordSet,
+	apex string,
+) (res []*route53.Change, err error) {
+	for _, r := range list {
+		if r.Name == nil || r.Type == nil {
+			return nil, fmt.Errorf("Record sets must have a name and a type")
+		}
+		set := *r
+		set.Name = aws.String(QualifyName(*r.Name, apex))
+		res = append(res, &route53.Change{
+			Action:            aws.String(route53.ChangeActionUpsert),
+			ResourceRecordSet: &set,
+		})
+	}
+	return
+}
@@ -0,0 +1,114 @@
+package got
+
+import (
+	"testing"
+)
+
+var serviceTemplate = `{
+  "ResourceRecordSets": [
+    {
+      "Name": "{{.name}}",
+      "Type": "A",
+      "TTL": 300,
+      "ResourceRecords": [{"Value": "{{.ip}}"}]
+    },
+    {
+      "Name": "www.{{.name}}",
+      "Type": "CNAME",
+      "TTL": 300,
+      "ResourceRecords": [{"Value": "{{.name}}.{{.zone}}."}]
+    },
+    {
+      "Name": "@",
+      "Type": "TXT",
+      "TTL": 300,
+      "ResourceRecords": [{"Value": "\"{{.name}}-verification={{.token}}\""}]
+    }
+  ]
+}`
+
+func TestRenderTemplate(t *testing.T) {
+	vars, err := ParseVars([]string{"name=foo", "ip=10.0.0.1", "token=a=b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := RenderTemplate(serviceTemplate, "example.com.", vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := TemplateChangeList(list, "example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "UPSERT foo A 300 10.0.0.1\n" +
+		"UPSERT www.foo CNAME 300 foo.example.com.\n" +
+		"UPSERT @ TXT 300 \"foo-verification=a=b\"\n"
+	if out := DescribeChanges(changes, "example.com."); out != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out)
+	}
+	if *changes[0].ResourceRecordSet.Name != "foo.example.com." {
+		t.Errorf(
+			"Expected name foo.example.com., got %s",
+			*changes[0].ResourceRecordSet.Name,
+		)
+	}
+}
+
+func TestRenderTemplateEscaping(t *testing.T) {
+	vars := map[string]string{
+		"name":  "foo",
+		"ip":    "10.0.0.1",
+		"token": "a\" }, {\"Value\": \"injected\\\nb",
+	}
+	list, err := RenderTemplate(serviceTemplate, "example.com.", vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 || len(list[2].ResourceRecords) != 1 {
+		t.Fatalf("Expected 3 record sets, the last with one value, got %v", list)
+	}
+	expected := "\"foo-verification=" + vars["token"] + "\""
+	if value := *list[2].ResourceRecords[0].Value; value != expected {
+		t.Errorf("Expected %q, got %q", expected, value)
+	}
+}
+
+func TestRenderTemplateErrors(t *testing.T) {
+	if _, err := RenderTemplate(
+		serviceTemplate,
+		"example.com.",
+		map[string]string{"name": "foo"},
+	); err == nil {
+		t.Error("Expected an error rendering with undefined variables")
+	}
+	if _, err := RenderTemplate(
+		"{{.name}}",
+		"example.com.",
+		map[string]string{"name": "foo"},
+	); err == nil {
+		t.Error("Expected an error rendering an invalid snapshot")
+	}
+	if _, err := ParseVars([]string{"name"}); err == nil {
+		t.Error("Expected an error parsing a variable without value")
+	}
+}
+
+var qualifynametests = []struct {
+	name     string
+	expected string
+}{
+	{"@", "example.com."},
+	{"www", "www.example.com."},
+	{"www.example.com.", "www.example.com."},
+	{"other.org.", "other.org."},
+}
+
+func TestQualifyName(t *testing.T) {
+	for _, tt := range qualifynametests {
+		t.Run(tt.name, func(t *testing.T) {
+			if out := QualifyName(tt.name, "example.com"); out != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, out)
+			}
+		})
+	}
+}
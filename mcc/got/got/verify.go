@@ -0,0 +1,242 @@
+package got
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/miekg/dns"
+)
+
+// Resolver queries a single nameserver for the values of a record.
+type Resolver interface {
+	Query(nameserver, name, typ string) ([]string, error)
+}
+
+// DNSResolver is a Resolver asking nameservers directly through DNS.
+type DNSResolver struct {
+	Port    string
+	Timeout time.Duration
+}
+
+// NewDNSResolver creates a DNSResolver using standard DNS port.
+func NewDNSResolver() *DNSResolver {
+	return &DNSResolver{
+		Port:    "53",
+		Timeout: 5 * time.Second,
+	}
+}
+
+// Query asks nameserver for name records of type typ, without recursion,
+// and returns their values in presentation format. Referrals are
+// accepted as answers, so NS records can be asked to parent zones.
+func (r *DNSResolver) Query(
+	nameserver, name, typ string,
+) (values []string, err error) {
+	qtype, ok := dns.StringToType[strings.ToUpper(typ)]
+	if !ok {
+		err = fmt.Errorf("Unknown record type %s", typ)
+		return
+	}
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = false
+	client := &dns.Client{Timeout: r.Timeout}
+	address := net.JoinHostPort(strings.TrimSuffix(nameserver, "."), r.Port)
+	in, _, err := client.Exchange(msg, address)
+	if err != nil {
+		return
+	}
+	if in.Rcode != dns.RcodeSuccess && in.Rcode != dns.RcodeNameError {
+		err = fmt.Errorf(
+			"%s answered %s for %s",
+			nameserver,
+			dns.RcodeToString[in.Rcode],
+			name,
+		)
+		return
+	}
+	for _, rr := range append(in.Answer, in.Ns...) {
+		if rr.Header().Rrtype != qtype ||
+			!strings.EqualFold(rr.Header().Name, dns.Fqdn(name)) {
+			continue
+		}
+		values = append(
+			values,
+			strings.TrimPrefix(rr.String(), rr.Header().String()),
+		)
+	}
+	return
+}
+
+// GetNameServers returns the authoritative nameservers of a hosted zone.
+// Private zones lack a delegation set, so their NS record is used instead.
+func GetNameServers(
+	zoneID string,
+	svc route53iface.Route53API,
+) (nameservers []string, err error) {
+	resp, err := svc.GetHostedZone(&route53.GetHostedZoneInput{
+		Id: aws.String(zoneID),
+	})
+	if err != nil {
+		return
+	}
+	if resp.DelegationSet != nil {
+		return aws.StringValueSlice(resp.DelegationSet.NameServers), nil
+	}
+	out, err := svc.ListResourceRecordSets(&route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(zoneID),
+		StartRecordName: resp.HostedZone.Name,
+		StartRecordType: aws.String(route53.RRTypeNs),
+		MaxItems:        aws.String("1"),
+	})
+	if err != nil {
+		return
+	}
+	for _, set := range out.ResourceRecordSets {
+		if *set.Type != route53.RRTypeNs {
+			continue
+		}
+		for _, record := range set.ResourceRecords {
+			nameservers = append(nameservers, *record.Value)
+		}
+	}
+	if len(nameservers) == 0 {
+		err = fmt.Errorf("No nameservers found for zone %s", zoneID)
+	}
+	return
+}
+
+// Expectation holds the values a record is expected to answer once a
+// change has propagated. Empty Values expect the record to be gone.
+type Expectation struct {
+	Name   string
+	Type   string
+	Values []string
+}
+
+// NewExpectations returns the Expectation for each record set changed in
+// the list, as left by its last change. Alias records are skipped, as
+// their values are resolved by Route53, and so are records with routing
+// policies, as nameservers answer one of the record sets sharing their
+// name and type, depending on the policy.
+func NewExpectations(changes []*route53.Change) (expected []Expectation) {
+	index := map[string]int{}
+	for _, change := range changes {
+		set := change.ResourceRecordSet
+		if set == nil {
+			continue
+		}
+		if set.AliasTarget != nil {
+			log.Printf("Can't verify alias record %s\n", *set.Name)
+			continue
+		}
+		if set.SetIdentifier != nil {
+			log.Printf(
+				"Can't verify record %s with a routing policy\n",
+				*set.Name,
+			)
+			continue
+		}
+		expectation := Expectation{
+			Name: *set.Name,
+			Type: *set.Type,
+		}
+		if *change.Action != route53.ChangeActionDelete {
+			for _, record := range set.ResourceRecords {
+				expectation.Values = append(
+					expectation.Values,
+					*record.Value,
+				)
+			}
+		}
+		key := strings.ToLower(
+			strings.TrimSuffix(expectation.Name, "."),
+		) + " " + expectation.Type
+		if i, ok := index[key]; ok {
+			expected[i] = expectation
+			continue
+		}
+		index[key] = len(expected)
+		expected = append(expected, expectation)
+	}
+	return
+}
+
+// Matches returns true if values are the same as the expected ones,
+// regardless of order, case and trailing dots.
+func (e Expectation) Matches(values []string) bool {
+	if len(values) != len(e.Values) {
+		return false
+	}
+	want := normalizeValues(e.Values)
+	got := normalizeValues(values)
+	for i := range want {
+		if want[i] != got[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeValues(values []string) (normalized []string) {
+	for _, value := range values {
+		normalized = append(
+			normalized,
+			strings.ToLower(strings.TrimSuffix(value, ".")),
+		)
+	}
+	sort.Strings(normalized)
+	return
+}
+
+// WaitForPropagation queries every nameserver until all of them answer
+// the expected values or timeout expires. It returns the nameservers
+// still lagging behind, if any.
+func WaitForPropagation(
+	nameservers []string,
+	expected []Expectation,
+	resolver Resolver,
+	timeout time.Duration,
+) (lagging []string) {
+	deadline := time.Now().Add(timeout)
+	lagging = nameservers
+	for {
+		lagging = laggingNameServers(lagging, expected, resolver)
+		if len(lagging) == 0 || time.Now().After(deadline) {
+			return
+		}
+		time.Sleep(Duration)
+	}
+}
+
+// laggingNameServers returns nameservers not answering what's expected.
+func laggingNameServers(
+	nameservers []string,
+	expected []Expectation,
+	resolver Resolver,
+) (lagging []string) {
+	for _, nameserver := range nameservers {
+		for _, expectation := range expected {
+			values, err := resolver.Query(
+				nameserver,
+				expectation.Name,
+				expectation.Type,
+			)
+			if err != nil && Verbose {
+				log.Println(err.Error())
+			}
+			if err != nil || !expectation.Matches(values) {
+				lagging = append(lagging, nameserver)
+				break
+			}
+		}
+	}
+	return
+}
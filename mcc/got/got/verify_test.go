@@ -0,0 +1,229 @@
+package got
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/miekg/dns"
+)
+
+// mockResolver answers from a map of nameserver to "name type" keys.
+type mockResolver struct {
+	answers map[string]map[string][]string
+}
+
+func (m *mockResolver) Query(
+	nameserver, name, typ string,
+) (values []string, err error) {
+	return m.answers[nameserver][name+" "+typ], nil
+}
+
+var expectation = Expectation{
+	Name:   "www.example.com.",
+	Type:   "CNAME",
+	Values: []string{"lb.example.com"},
+}
+
+var wfptest = []struct {
+	name    string
+	answers map[string]map[string][]string
+	lagging []string
+}{
+	{
+		"all",
+		map[string]map[string][]string{
+			nsOne: {"www.example.com. CNAME": {"lb.example.com."}},
+			nsTwo: {"www.example.com. CNAME": {"LB.example.com."}},
+		},
+		nil,
+	},
+	{
+		"one",
+		map[string]map[string][]string{
+			nsOne: {"www.example.com. CNAME": {"lb.example.com."}},
+			nsTwo: {"www.example.com. CNAME": {"old.example.com."}},
+		},
+		[]string{nsTwo},
+	},
+	{
+		"none",
+		map[string]map[string][]string{},
+		[]string{nsOne, nsTwo},
+	},
+}
+
+func TestWaitForPropagation(t *testing.T) {
+	defer func(duration time.Duration) { Duration = duration }(Duration)
+	Duration = time.Millisecond
+	for _, tt := range wfptest {
+		t.Run(tt.name, func(t *testing.T) {
+			lagging := WaitForPropagation(
+				[]string{nsOne, nsTwo},
+				[]Expectation{expectation},
+				&mockResolver{answers: tt.answers},
+				10*time.Millisecond,
+			)
+			if !reflect.DeepEqual(lagging, tt.lagging) {
+				t.Errorf(
+					"Expected %v lagging, got %v",
+					tt.lagging,
+					lagging,
+				)
+			}
+		})
+	}
+}
+
+func TestNewExpectations(t *testing.T) {
+	changes := []*route53.Change{
+		{
+			Action:            pstr("UPSERT"),
+			ResourceRecordSet: onerecordA,
+		},
+		{
+			Action: pstr("UPSERT"),
+			ResourceRecordSet: &route53.ResourceRecordSet{
+				Name: &two,
+				Type: &A,
+				AliasTarget: &route53.AliasTarget{
+					DNSName: &awsCname,
+				},
+			},
+		},
+		{
+			Action: pstr("DELETE"),
+			ResourceRecordSet: &route53.ResourceRecordSet{
+				Name: &two,
+				Type: &AAAA,
+				ResourceRecords: NewResourceRecordList(
+					[]string{"::1"},
+				),
+			},
+		},
+	}
+	expected := NewExpectations(changes)
+	if len(expected) != 2 {
+		t.Fatalf("Expected 2 expectations, got %d", len(expected))
+	}
+	if expected[0].Name != one || expected[0].Type != A {
+		t.Errorf("Unexpected expectation %v", expected[0])
+	}
+	if len(expected[1].Values) != 0 {
+		t.Errorf("Deleted records shouldn't expect values")
+	}
+}
+
+func TestNewExpectationsSameRecordSet(t *testing.T) {
+	weighted := newRecordSet("api.example.com.", "A", 60, "10.0.0.1")
+	weighted.SetIdentifier = pstr("blue")
+	weighted.Weight = aws.Int64(100)
+	changes := []*route53.Change{
+		{
+			Action:            pstr("DELETE"),
+			ResourceRecordSet: newRecordSet("www.example.com.", "A", 60, "10.0.0.1"),
+		},
+		{
+			Action:            pstr("CREATE"),
+			ResourceRecordSet: newRecordSet("WWW.example.com", "A", 60, "10.0.0.2"),
+		},
+		{
+			Action:            pstr("UPSERT"),
+			ResourceRecordSet: weighted,
+		},
+	}
+	expected := NewExpectations(changes)
+	if len(expected) != 1 {
+		t.Fatalf("Expected 1 expectation, got %v", expected)
+	}
+	if !reflect.DeepEqual(expected[0].Values, []string{"10.0.0.2"}) {
+		t.Errorf("Expected the values of the last change, got %v", expected[0])
+	}
+}
+
+func TestGetNameServers(t *testing.T) {
+	out, err := GetNameServers("test", &mockRoute53Client{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, []string{nsOne, nsTwo}) {
+		t.Errorf("Unexpected nameservers %v", out)
+	}
+}
+
+// startDNSServer starts a local DNS server answering records in zone.
+func startDNSServer(t *testing.T, zone []string) (
+	server *dns.Server,
+	port string,
+) {
+	records := []dns.RR{}
+	for _, line := range zone {
+		rr, err := dns.NewRR(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, rr)
+	}
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server = &dns.Server{
+		PacketConn: conn,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			m := new(dns.Msg)
+			m.SetReply(r)
+			m.Authoritative = true
+			question := r.Question[0]
+			for _, rr := range records {
+				if strings.EqualFold(rr.Header().Name, question.Name) &&
+					rr.Header().Rrtype == question.Qtype {
+					m.Answer = append(m.Answer, rr)
+				}
+			}
+			_ = w.WriteMsg(m)
+		}),
+	}
+	go func() { _ = server.ActivateAndServe() }()
+	_, port, _ = net.SplitHostPort(conn.LocalAddr().String())
+	return
+}
+
+func TestDNSResolverQuery(t *testing.T) {
+	server, port := startDNSServer(t, []string{
+		"www.example.com. 300 IN CNAME lb.example.com.",
+		"lb.example.com. 300 IN A 10.0.0.1",
+		"lb.example.com. 300 IN A 10.0.0.2",
+		"example.com. 300 IN TXT \"v=spf1 -all\"",
+	})
+	defer server.Shutdown()
+	resolver := NewDNSResolver()
+	resolver.Port = port
+	var dqtest = []struct {
+		name, typ string
+		values    []string
+	}{
+		{"www.example.com", "CNAME", []string{"lb.example.com."}},
+		{"lb.example.com.", "A", []string{"10.0.0.1", "10.0.0.2"}},
+		{"example.com.", "TXT", []string{"\"v=spf1 -all\""}},
+		{"missing.example.com.", "A", nil},
+	}
+	for _, tt := range dqtest {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := resolver.Query("127.0.0.1", tt.name, tt.typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(values, tt.values) {
+				t.Errorf("Expected %v, got %v", tt.values, values)
+			}
+		})
+	}
+	if _, err := resolver.Query("127.0.0.1", "example.com", "BOGUS"); err == nil {
+		t.Error("Unknown types should fail")
+	}
+}
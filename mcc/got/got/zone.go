@@ -0,0 +1,281 @@
+package got
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/miekg/dns"
+)
+
+// ZoneSelector describes which hosted zone to work on. Zones are
+// selected by ID or by Name, and in the latter case Private allows to
+// choose in between public and private zones sharing the same name.
+type ZoneSelector struct {
+	Name    string
+	ID      string
+	Private *bool
+}
+
+// String describes the selected zone for messages.
+func (s ZoneSelector) String() string {
+	if s.ID != "" {
+		return s.ID
+	}
+	if s.Private != nil && *s.Private {
+		return fmt.Sprintf("private %s", s.Name)
+	}
+	if s.Private != nil {
+		return fmt.Sprintf("public %s", s.Name)
+	}
+	return s.Name
+}
+
+// IsPrivate returns true if zone is a private hosted zone.
+func IsPrivate(zone *route53.HostedZone) bool {
+	return zone.Config != nil && aws.BoolValue(zone.Config.PrivateZone)
+}
+
+// sameName returns true if both names are the same domain.
+func sameName(a, b string) bool {
+	return strings.EqualFold(
+		strings.TrimSuffix(a, "."),
+		strings.TrimSuffix(b, "."),
+	)
+}
+
+// FindHostedZone returns the only hosted zone matching selector, or an
+// error if there's none or several of them.
+func FindHostedZone(
+	selector ZoneSelector,
+	svc route53iface.Route53API,
+) (zone *route53.HostedZone, err error) {
+	if selector.ID != "" {
+		var resp *route53.GetHostedZoneOutput
+		resp, err = svc.GetHostedZone(&route53.GetHostedZoneInput{
+			Id: aws.String(selector.ID),
+		})
+		if err != nil {
+			return
+		}
+		zone = resp.HostedZone
+		if selector.Name != "" && !sameName(*zone.Name, selector.Name) {
+			err = fmt.Errorf(
+				"Zone %s is %s, not %s",
+				selector.ID,
+				*zone.Name,
+				selector.Name,
+			)
+		}
+		return
+	}
+	if selector.Name == "" {
+		err = fmt.Errorf("No zone name or ID specified")
+		return
+	}
+	matches := []*route53.HostedZone{}
+	params := &route53.ListHostedZonesByNameInput{
+		DNSName:  aws.String(selector.Name),
+		MaxItems: aws.String("100"),
+	}
+	for {
+		var resp *route53.ListHostedZonesByNameOutput
+		resp, err = svc.ListHostedZonesByName(params)
+		if err != nil {
+			return
+		}
+		last := true
+		for _, candidate := range resp.HostedZones {
+			if !sameName(*candidate.Name, selector.Name) {
+				break
+			}
+			last = false
+			if selector.Private == nil ||
+				*selector.Private == IsPrivate(candidate) {
+				matches = append(matches, candidate)
+			}
+		}
+		// Results are sorted by name, so there's no need to go on
+		// once a different name is found.
+		if last || !aws.BoolValue(resp.IsTruncated) {
+			break
+		}
+		params.DNSName = resp.NextDNSName
+		params.HostedZoneId = resp.NextHostedZoneId
+	}
+	switch len(matches) {
+	case 0:
+		err = fmt.Errorf("No hosted zone found for %s", selector)
+	case 1:
+		zone = matches[0]
+	default:
+		ids := []string{}
+		for _, match := range matches {
+			ids = append(ids, *match.Id)
+		}
+		err = fmt.Errorf(
+			"Several hosted zones found for %s: %s. "+
+				"Specify one of them by ID, or whether it's private",
+			selector,
+			strings.Join(ids, ", "),
+		)
+	}
+	return
+}
+
+// ListHostedZones returns all the hosted zones in the account. It may
+// issue more than one request as each returns a fixed amount of entries
+// at most.
+func ListHostedZones(
+	svc route53iface.Route53API,
+) (zones []*route53.HostedZone, err error) {
+	params := &route53.ListHostedZonesInput{}
+	for respIsTruncated := true; respIsTruncated; {
+		var resp *route53.ListHostedZonesOutput
+		resp, err = svc.ListHostedZones(params)
+		if err != nil {
+			return
+		}
+		params.Marker = resp.NextMarker
+		respIsTruncated = aws.BoolValue(resp.IsTruncated)
+		zones = append(zones, resp.HostedZones...)
+	}
+	return
+}
+
+// FormatHostedZones returns a line per zone with its ID, name,
+// visibility, and amount of record sets.
+func FormatHostedZones(zones []*route53.HostedZone) (output string) {
+	for _, zone := range zones {
+		visibility := "public"
+		if IsPrivate(zone) {
+			visibility = "private"
+		}
+		output += fmt.Sprintf(
+			"%s\t%s\t%s\t%d\n",
+			strings.TrimPrefix(*zone.Id, "/hostedzone/"),
+			*zone.Name,
+			visibility,
+			aws.Int64Value(zone.ResourceRecordSetCount),
+		)
+	}
+	return
+}
+
+// VPC identifies a VPC to associate private hosted zones to.
+type VPC struct {
+	ID     string
+	Region string
+}
+
+// DescribeHostedZone returns a line describing the hosted zone called
+// name CreateHostedZone would create, tab separated as FormatHostedZones
+// lists them.
+func DescribeHostedZone(name, comment string, vpc *VPC) string {
+	visibility := "public"
+	if vpc != nil {
+		visibility = fmt.Sprintf("private\t%s\t%s", vpc.ID, vpc.Region)
+	}
+	return fmt.Sprintf(
+		"CREATE\t%s\t%s\t%q\n",
+		dns.Fqdn(name),
+		visibility,
+		comment,
+	)
+}
+
+// CreateHostedZone creates a new hosted zone called name. If vpc is not
+// nil, the zone is private and associated to it.
+func CreateHostedZone(
+	name string,
+	comment string,
+	vpc *VPC,
+	svc route53iface.Route53API,
+) (*route53.CreateHostedZoneOutput, error) {
+	params := &route53.CreateHostedZoneInput{
+		Name:            aws.String(name),
+		CallerReference: aws.String(time.Now().Format(time.RFC3339Nano)),
+		HostedZoneConfig: &route53.HostedZoneConfig{
+			Comment:     aws.String(comment),
+			PrivateZone: aws.Bool(vpc != nil),
+		},
+	}
+	if vpc != nil {
+		params.VPC = &route53.VPC{
+			VPCId:     aws.String(vpc.ID),
+			VPCRegion: aws.String(vpc.Region),
+		}
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return svc.CreateHostedZone(params)
+}
+
+// EmptyChangeList generates a list of changes for DELETEing every record
+// set in list but the apex SOA and NS ones, which can't be removed.
+func EmptyChangeList(
+	list []*route53.ResourceRecordSet,
+	apex string,
+) (res []*route53.Change) {
+	for _, set := range list {
+		if isApexAuthority(set, apex) {
+			continue
+		}
+		res = append(res, &route53.Change{
+			Action:            aws.String(route53.ChangeActionDelete),
+			ResourceRecordSet: set,
+		})
+	}
+	return
+}
+
+// DeleteHostedZone deletes the hosted zone identified by zoneID, which
+// must only contain its SOA and NS records.
+func DeleteHostedZone(
+	zoneID string,
+	svc route53iface.Route53API,
+) (*route53.DeleteHostedZoneOutput, error) {
+	return svc.DeleteHostedZone(&route53.DeleteHostedZoneInput{
+		Id: aws.String(zoneID),
+	})
+}
+
+// DescribeHostedZoneDeletion returns a line describing the deletion of
+// the hosted zone identified by zoneID and called name, tab separated.
+func DescribeHostedZoneDeletion(zoneID, name string) string {
+	return fmt.Sprintf("DELETE\t%s\t%s\n", dns.Fqdn(name), zoneID)
+}
+
+// DescribeVPCAssociation returns a line describing the association of the
+// hosted zone identified by zoneID and called name with vpc, tab
+// separated.
+func DescribeVPCAssociation(zoneID, name string, vpc VPC) string {
+	return fmt.Sprintf(
+		"ASSOCIATE\t%s\t%s\t%s\t%s\n",
+		dns.Fqdn(name),
+		zoneID,
+		vpc.ID,
+		vpc.Region,
+	)
+}
+
+// AssociateVPC associates a private hosted zone with one more VPC.
+func AssociateVPC(
+	zoneID string,
+	vpc VPC,
+	svc route53iface.Route53API,
+) (*route53.AssociateVPCWithHostedZoneOutput, error) {
+	return svc.AssociateVPCWithHostedZone(
+		&route53.AssociateVPCWithHostedZoneInput{
+			HostedZoneId: aws.String(zoneID),
+			VPC: &route53.VPC{
+				VPCId:     aws.String(vpc.ID),
+				VPCRegion: aws.String(vpc.Region),
+			},
+		},
+	)
+}
@@ -0,0 +1,179 @@
+package got
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+)
+
+// mockZonesClient mocks Route53 for an account with several zones.
+type mockZonesClient struct {
+	route53iface.Route53API
+	zones []*route53.HostedZone
+}
+
+// reversedName returns name labels in reverse order, as Route53 sorts
+// zones by them.
+func reversedName(name string) string {
+	labels := strings.Split(strings.TrimSuffix(name, "."), ".")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	return strings.Join(labels, ".")
+}
+
+// ListHostedZonesByName mocks route53.ListHostedZonesByName returning zones
+// sorted by name from DNSName on.
+func (m *mockZonesClient) ListHostedZonesByName(
+	params *route53.ListHostedZonesByNameInput,
+) (out *route53.ListHostedZonesByNameOutput, err error) {
+	out = &route53.ListHostedZonesByNameOutput{IsTruncated: aws.Bool(false)}
+	zones := append([]*route53.HostedZone{}, m.zones...)
+	sort.SliceStable(zones, func(i, j int) bool {
+		return reversedName(*zones[i].Name) < reversedName(*zones[j].Name)
+	})
+	for _, zone := range zones {
+		if reversedName(*zone.Name) >= reversedName(*params.DNSName) {
+			out.HostedZones = append(out.HostedZones, zone)
+		}
+	}
+	return
+}
+
+func (m *mockZonesClient) ListHostedZones(
+	params *route53.ListHostedZonesInput,
+) (out *route53.ListHostedZonesOutput, err error) {
+	out = &route53.ListHostedZonesOutput{
+		IsTruncated: aws.Bool(false),
+		HostedZones: m.zones,
+	}
+	return
+}
+
+func (m *mockZonesClient) GetHostedZone(
+	params *route53.GetHostedZoneInput,
+) (out *route53.GetHostedZoneOutput, err error) {
+	for _, zone := range m.zones {
+		if *zone.Id == *params.Id {
+			out = &route53.GetHostedZoneOutput{HostedZone: zone}
+			return
+		}
+	}
+	err = fmt.Errorf("No hosted zone found with ID %s", *params.Id)
+	return
+}
+
+func newHostedZone(id, name string, private bool) *route53.HostedZone {
+	return &route53.HostedZone{
+		Id:   aws.String(id),
+		Name: aws.String(name),
+		Config: &route53.HostedZoneConfig{
+			PrivateZone: aws.Bool(private),
+		},
+	}
+}
+
+var zonesClient = &mockZonesClient{
+	zones: []*route53.HostedZone{
+		newHostedZone("/hostedzone/Z1", "example.com.", false),
+		newHostedZone("/hostedzone/Z2", "example.com.", true),
+		newHostedZone("/hostedzone/Z3", "sub.example.com.", false),
+		newHostedZone("/hostedzone/Z4", "example.org.", false),
+	},
+}
+
+var fhztest = []struct {
+	selector ZoneSelector
+	id       string
+	fails    bool
+}{
+	{ZoneSelector{Name: "example.org"}, "/hostedzone/Z4", false},
+	{ZoneSelector{Name: "sub.example.com."}, "/hostedzone/Z3", false},
+	{ZoneSelector{Name: "example.com"}, "", true},
+	{ZoneSelector{Name: "example.com", Private: aws.Bool(true)}, "/hostedzone/Z2", false},
+	{ZoneSelector{Name: "example.com", Private: aws.Bool(false)}, "/hostedzone/Z1", false},
+	{ZoneSelector{Name: "example.net"}, "", true},
+	{ZoneSelector{Name: "ample.com"}, "", true},
+	{ZoneSelector{ID: "/hostedzone/Z2"}, "/hostedzone/Z2", false},
+	{ZoneSelector{ID: "/hostedzone/Z2", Name: "example.org"}, "", true},
+	{ZoneSelector{}, "", true},
+}
+
+func TestFindHostedZone(t *testing.T) {
+	for _, tt := range fhztest {
+		t.Run(tt.selector.String(), func(t *testing.T) {
+			zone, err := FindHostedZone(tt.selector, zonesClient)
+			if tt.fails {
+				if err == nil {
+					t.Errorf("Expected failure, got %s", *zone.Id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *zone.Id != tt.id {
+				t.Errorf("Expected %s, got %s", tt.id, *zone.Id)
+			}
+		})
+	}
+}
+
+func TestFormatHostedZones(t *testing.T) {
+	out := FormatHostedZones(zonesClient.zones[:2])
+	expected := "Z1\texample.com.\tpublic\t0\n" +
+		"Z2\texample.com.\tprivate\t0\n"
+	if out != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out)
+	}
+}
+
+func TestDescribeHostedZone(t *testing.T) {
+	out := DescribeHostedZone("example.com", "Public zone", nil)
+	if expected := "CREATE\texample.com.\tpublic\t\"Public zone\"\n"; out != expected {
+		t.Errorf("Expected %q, got %q", expected, out)
+	}
+	out = DescribeHostedZone(
+		"example.com.",
+		"",
+		&VPC{ID: "vpc-1", Region: "eu-west-1"},
+	)
+	if expected := "CREATE\texample.com.\tprivate\tvpc-1\teu-west-1\t\"\"\n"; out != expected {
+		t.Errorf("Expected %q, got %q", expected, out)
+	}
+	out = DescribeHostedZoneDeletion("Z1", "example.com")
+	if expected := "DELETE\texample.com.\tZ1\n"; out != expected {
+		t.Errorf("Expected %q, got %q", expected, out)
+	}
+	out = DescribeVPCAssociation(
+		"Z1",
+		"example.com.",
+		VPC{ID: "vpc-1", Region: "eu-west-1"},
+	)
+	if expected := "ASSOCIATE\texample.com.\tZ1\tvpc-1\teu-west-1\n"; out != expected {
+		t.Errorf("Expected %q, got %q", expected, out)
+	}
+}
+
+func TestEmptyChangeList(t *testing.T) {
+	list := []*route53.ResourceRecordSet{
+		newRecordSet("example.com.", "SOA", 900, "ns-1. admin. 1 7200 900 1209600 86400"),
+		newRecordSet("example.com.", "NS", 172800, "ns-1."),
+		newRecordSet("sub.example.com.", "NS", 172800, "ns-2."),
+		newRecordSet("www.example.com.", "A", 300, "10.0.0.1"),
+	}
+	changes := EmptyChangeList(list, "example.com.")
+	if len(changes) != 2 {
+		t.Errorf("Expected 2 changes, got %d", len(changes))
+	}
+	for _, change := range changes {
+		if *change.Action != "DELETE" {
+			t.Errorf("Expected DELETE action, got %s", *change.Action)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package roosa
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// Resolver resolves names out of the domain to their addresses.
+type Resolver interface {
+	LookupHost(name string) (addrs []string, err error)
+}
+
+// NetResolver is a Resolver using the system resolver.
+type NetResolver struct{}
+
+// LookupHost returns the addresses name resolves to.
+func (r NetResolver) LookupHost(name string) ([]string, error) {
+	return net.LookupHost(name)
+}
+
+// Pattern identifies names of resources which may be deprovisioned, and
+// then claimed by anyone else, for the service providing them.
+type Pattern struct {
+	Service    string
+	Expression *regexp.Regexp
+}
+
+// DeprovisionablePatterns are the patterns audits check targets against.
+var DeprovisionablePatterns = []*Pattern{
+	{"S3 website", regexp.MustCompile(`(^|\.)s3-website[.-][a-z0-9-]+\.amazonaws\.com\.?$`)},
+	{"S3", regexp.MustCompile(`\.s3([.-][a-z0-9-]+)?\.amazonaws\.com\.?$`)},
+	{"CloudFront", regexp.MustCompile(`\.cloudfront\.net\.?$`)},
+	{"Elastic Beanstalk", regexp.MustCompile(`\.elasticbeanstalk\.com\.?$`)},
+	{"Heroku", regexp.MustCompile(`\.(herokuapp|herokudns|herokussl)\.com\.?$`)},
+	{"GitHub Pages", regexp.MustCompile(`\.github\.io\.?$`)},
+	{"Azure", regexp.MustCompile(`\.(azurewebsites|cloudapp|trafficmanager|blob\.core\.windows)\.net\.?$`)},
+	{"Fastly", regexp.MustCompile(`\.fastly\.net\.?$`)},
+	{"Shopify", regexp.MustCompile(`\.myshopify\.com\.?$`)},
+}
+
+// Finding kinds, from the most to the least severe.
+const (
+	// Takeover is a target of a deprovisionable service which doesn't
+	// resolve, so anyone may claim it.
+	Takeover = "takeover"
+	// Dangling is a target which doesn't resolve.
+	Dangling = "dangling"
+	// Deprovisionable is a target of a deprovisionable service which
+	// still resolves.
+	Deprovisionable = "deprovisionable"
+)
+
+// Finding is a risk found auditing a record pointing out of the domain.
+type Finding struct {
+	Kind       string
+	Name       string
+	Type       string
+	Target     string
+	Service    string
+	Error      string
+	Dependents []string
+}
+
+// String returns a line describing f, followed by the names depending on
+// the record.
+func (f *Finding) String() (output string) {
+	output = fmt.Sprintf("%s: %s %s %s", f.Kind, f.Name, f.Type, f.Target)
+	if f.Service != "" {
+		output += fmt.Sprintf(" (%s)", f.Service)
+	}
+	if f.Error != "" {
+		output += fmt.Sprintf(": %s", f.Error)
+	}
+	for _, dependent := range f.Dependents {
+		output += fmt.Sprintf("\n\t%s", dependent)
+	}
+	return
+}
+
+// Risky returns true unless f is only informational.
+func (f *Finding) Risky() bool {
+	return f.Kind != Deprovisionable
+}
+
+// MatchPattern returns the deprovisionable pattern matching name, or nil.
+func MatchPattern(name string) *Pattern {
+	name = strings.ToLower(name)
+	for _, pattern := range DeprovisionablePatterns {
+		if pattern.Expression.MatchString(name) {
+			return pattern
+		}
+	}
+	return nil
+}
+
+// dependents returns the names of all records resolving through node.
+func dependents(node *Node) (names []string) {
+	for _, child := range node.Children() {
+		names = append(names, child.Name())
+		names = append(names, dependents(child)...)
+	}
+	return
+}
+
+// Audit resolves the targets out of the domain of the reference trees
+// with resolver, and returns the findings for those which don't resolve
+// or belong to deprovisionable services.
+func (rtl *ReferenceTreeList) Audit(resolver Resolver) (findings []*Finding) {
+	resolved := map[string]error{}
+	for _, node := range rtl.treeNodes() {
+		for _, reference := range node.External() {
+			target := reference.Target
+			err, ok := resolved[target]
+			if !ok {
+				var addrs []string
+				addrs, err = resolver.LookupHost(target)
+				if err == nil && len(addrs) == 0 {
+					err = fmt.Errorf("No addresses found")
+				}
+				resolved[target] = err
+			}
+			finding := &Finding{
+				Name:       node.Name(),
+				Type:       node.Type(),
+				Target:     target,
+				Dependents: dependents(node),
+			}
+			pattern := MatchPattern(target)
+			if pattern != nil {
+				finding.Service = pattern.Service
+			}
+			switch {
+			case err != nil && pattern != nil:
+				finding.Kind = Takeover
+			case err != nil:
+				finding.Kind = Dangling
+			case pattern != nil:
+				finding.Kind = Deprovisionable
+			default:
+				continue
+			}
+			if err != nil {
+				finding.Error = err.Error()
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return
+}
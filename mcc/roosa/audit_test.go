@@ -0,0 +1,89 @@
+package roosa
+
+import (
+	"net"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+type mockResolver struct {
+	hosts map[string][]string
+}
+
+func (r *mockResolver) LookupHost(name string) ([]string, error) {
+	if addrs, ok := r.hosts[name]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+var audittests = []struct {
+	record  *route53.ResourceRecordSet
+	kind    string
+	service string
+}{
+	{
+		record: newRecord("app.example.com.", "CNAME", "example-app.herokuapp.com"),
+		kind:   Takeover, service: "Heroku",
+	},
+	{
+		record: newRecord("cdn.example.com.", "CNAME", "d111111abcdef8.cloudfront.net"),
+		kind:   Deprovisionable, service: "CloudFront",
+	},
+	{
+		record: newRecord("old.example.com.", "CNAME", "gone.example.org"),
+		kind:   Dangling,
+	},
+	{
+		record: newRecord("ok.example.com.", "CNAME", "alive.example.org"),
+	},
+	{
+		record: newAlias("static.example.com.", "A", "s3-website-eu-west-1.amazonaws.com."),
+		kind:   Deprovisionable, service: "S3 website",
+	},
+	{
+		record: newRecord("eb.example.com.", "CNAME", "myapp.eu-west-1.elasticbeanstalk.com"),
+		kind:   Takeover, service: "Elastic Beanstalk",
+	},
+}
+
+func TestAudit(t *testing.T) {
+	resolver := &mockResolver{hosts: map[string][]string{
+		"d111111abcdef8.cloudfront.net":       {"10.0.0.1"},
+		"alive.example.org":                   {"10.0.0.2"},
+		"s3-website-eu-west-1.amazonaws.com.": {"10.0.0.3"},
+	}}
+	for _, tt := range audittests {
+		records := []*route53.ResourceRecordSet{
+			tt.record,
+			newRecord("www."+*tt.record.Name, "CNAME", *tt.record.Name),
+		}
+		findings := NewReferenceTreeList(records).Audit(resolver)
+		if tt.kind == "" {
+			if len(findings) != 0 {
+				t.Errorf("%s shouldn't have findings: %v", *tt.record.Name, findings)
+			}
+			continue
+		}
+		if len(findings) != 1 {
+			t.Errorf("%s should have a finding: %v", *tt.record.Name, findings)
+			continue
+		}
+		finding := findings[0]
+		if finding.Kind != tt.kind || finding.Service != tt.service {
+			t.Errorf(
+				"%s should be %s (%s), got %s (%s)",
+				*tt.record.Name,
+				tt.kind,
+				tt.service,
+				finding.Kind,
+				finding.Service,
+			)
+		}
+		if len(finding.Dependents) != 1 ||
+			finding.Dependents[0] != "www."+*tt.record.Name {
+			t.Errorf("Unexpected dependents %v", finding.Dependents)
+		}
+	}
+}
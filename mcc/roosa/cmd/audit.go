@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/roosa"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit [flags]",
+	Short: "Find dangling records and subdomain takeover risks",
+	Long: `Resolve every target out of the domain and report the records whose
+target doesn't resolve, or belongs to a service resources can be
+deprovisioned from and claimed by someone else.
+Exits with status 1 when dangling or takeover records are found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		risky := false
+		for _, finding := range referenceTreeList().Audit(roosa.NetResolver{}) {
+			fmt.Println(finding)
+			risky = risky || finding.Risky()
+		}
+		if risky {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(auditCmd)
+}
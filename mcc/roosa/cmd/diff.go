@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/roosa"
+)
+
+// readSnapshot returns the snapshot saved in path, exiting on failure.
+func readSnapshot(path string) *roosa.Snapshot {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	snapshot, err := roosa.ReadSnapshot(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return snapshot
+}
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <snapshot> [<snapshot>]",
+	Short: "Compare reference tree snapshots",
+	Long: `Compare a snapshot saved by roosa tree --save with another one, or
+with the current reference trees of the selected zones or files, showing
+which dependency chains appeared (+), disappeared (-) or were re-pointed (~).
+Exits with status 1 when differences are found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 || len(args) > 2 {
+			log.Fatal("One or two snapshots must be specified")
+		}
+		from := readSnapshot(args[0])
+		var to *roosa.Snapshot
+		if len(args) > 1 {
+			to = readSnapshot(args[1])
+		} else {
+			to = referenceTreeList().Snapshot()
+		}
+		diff := roosa.DiffSnapshots(from, to)
+		fmt.Print(diff)
+		if !diff.Empty() {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(diffCmd)
+}
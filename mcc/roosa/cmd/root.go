@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/roosa"
+)
+
+var zoneNames, files []string
+var origin string
+var allZones, resources, matchPTR bool
+var maxDepth int
+
+// RootCmd represents the base command when called without any subcommands
+var RootCmd = &cobra.Command{
+	Use:   "roosa",
+	Short: "DNS records relationship detection and visualization",
+	Long: `roosa builds the reference trees of DNS records, relating records
+to the ones they point to, from Route53 hosted zones, zone files or JSON
+exports, to print, audit or compare them.`,
+}
+
+// Execute adds all child commands to the root command sets flags appropriately.
+// This is called by main.main(). It only needs to happen once to the rootCmd.
+func Execute() {
+	if err := RootCmd.Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+}
+
+// newSession returns an AWS session, exiting on failure.
+func newSession() *session.Session {
+	sess, err := session.NewSession()
+	if err != nil {
+		log.Fatalf("Failed to create session: %s", err)
+	}
+	return sess
+}
+
+// route53Records returns the records of the selected hosted zones.
+func route53Records(svc *route53.Route53) []*route53.ResourceRecordSet {
+	zoneIDs := []string{}
+	if allZones {
+		for _, zone := range roosa.ListHostedZones(svc) {
+			zoneIDs = append(zoneIDs, *zone.Id)
+		}
+	} else {
+		for _, name := range zoneNames {
+			zoneID, err := roosa.GetZoneID(name, svc)
+			if err != nil {
+				log.Fatal(err)
+			}
+			zoneIDs = append(zoneIDs, zoneID)
+		}
+	}
+	return roosa.GetZonesResourceRecordSets(zoneIDs, svc)
+}
+
+// fileRecords returns the records in the selected files.
+func fileRecords() (records []*route53.ResourceRecordSet) {
+	for _, path := range files {
+		fileRecords, err := roosa.ReadRecordsFile(path, origin)
+		if err != nil {
+			log.Fatal(err)
+		}
+		records = append(records, fileRecords...)
+	}
+	return
+}
+
+// referenceTreeList returns the reference trees of the selected zones or
+// files, printing the warnings found building them.
+func referenceTreeList() *roosa.ReferenceTreeList {
+	if len(zoneNames) == 0 && !allZones && len(files) == 0 {
+		log.Fatal("No zone or file specified")
+	}
+	var records []*route53.ResourceRecordSet
+	if len(files) > 0 {
+		records = fileRecords()
+	} else {
+		records = route53Records(route53.New(newSession()))
+	}
+	referenceTreeList := roosa.NewReferenceTreeList(records)
+	referenceTreeList.MaxDepth = maxDepth
+	referenceTreeList.MatchPTR = matchPTR
+	if resources {
+		sess := newSession()
+		inventory := roosa.NewInventory()
+		inventory.AddEC2(ec2.New(sess))
+		inventory.AddELB(elb.New(sess))
+		inventory.AddELBv2(elbv2.New(sess))
+		inventory.AddRDS(rds.New(sess))
+		inventory.AddCloudFront(cloudfront.New(sess))
+		referenceTreeList.MapResources(inventory)
+	}
+	for _, warning := range referenceTreeList.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+	}
+	return referenceTreeList
+}
+
+func init() {
+	// Here you will define your flags and configuration settings.
+	// Cobra supports Persistent Flags, which, if defined here,
+	// will be global for your application.
+
+	RootCmd.PersistentFlags().StringSliceVarP(
+		&zoneNames,
+		"zone",
+		"",
+		[]string{},
+		"Names of the hosted zones to traverse.",
+	)
+	RootCmd.PersistentFlags().BoolVarP(
+		&allZones,
+		"all",
+		"",
+		false,
+		"Traverse all hosted zones in the account.",
+	)
+	RootCmd.PersistentFlags().StringSliceVarP(
+		&files,
+		"file",
+		"",
+		[]string{},
+		"Zone files or JSON exports to traverse instead of hosted zones.",
+	)
+	RootCmd.PersistentFlags().StringVarP(
+		&origin,
+		"origin",
+		"",
+		"",
+		"Origin of relative names in zone files without $ORIGIN.",
+	)
+	RootCmd.PersistentFlags().BoolVarP(
+		&resources,
+		"resources",
+		"",
+		false,
+		"Map records to the EC2, ELB, RDS and CloudFront resources behind them.",
+	)
+	RootCmd.PersistentFlags().BoolVarP(
+		&matchPTR,
+		"ptr",
+		"",
+		false,
+		"Match PTR records in reverse zones to the address records they point to.",
+	)
+	RootCmd.PersistentFlags().IntVarP(
+		&maxDepth,
+		"max-depth",
+		"",
+		roosa.DefaultMaxDepth,
+		"Warn about chains of more references, 0 to disable.",
+	)
+}
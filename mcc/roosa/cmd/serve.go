@@ -0,0 +1,50 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/roosa"
+)
+
+var listen string
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve [flags]",
+	Short: "Explore the reference trees in a browser",
+	Long: `Start a local HTTP server with a page to explore the reference trees,
+which can be collapsed and searched by name, with records coloured by type
+and names out of the domain highlighted. The trees are built once, on
+start.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		referenceTreeList := referenceTreeList()
+		handler := roosa.NewHandler(roosa.NewGraph(referenceTreeList.Roots()))
+		log.Printf("Serving on http://%s/", listen)
+		log.Fatal(http.ListenAndServe(listen, handler))
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(serveCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// serveCmd.PersistentFlags().String("foo", "", "A help for foo")
+	serveCmd.PersistentFlags().StringVarP(
+		&listen,
+		"listen",
+		"",
+		"localhost:8080",
+		"Address to listen on.",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// serveCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
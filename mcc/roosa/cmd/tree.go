@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/roosa"
+)
+
+var format, dependsOn, save string
+
+// treeCmd represents the tree command
+var treeCmd = &cobra.Command{
+	Use:   "tree [flags]",
+	Short: "Print the reference trees of DNS records",
+	Long: `Print the reference trees of the records, sorted by name, as indented
+text, a Graphviz dot graph, a Mermaid flowchart, or JSON vertices and edges.
+With --depends-on, list the records depending on a name or address instead.
+With --save, also save a snapshot of the trees to compare with roosa diff.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		switch format {
+		case "text", "dot", "json", "mermaid":
+		default:
+			log.Fatalf("Unknown output format %s", format)
+		}
+		referenceTreeList := referenceTreeList()
+		if save != "" {
+			file, err := os.Create(save)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer file.Close()
+			err = roosa.WriteSnapshot(file, referenceTreeList.Snapshot())
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		if dependsOn != "" {
+			for _, dependency := range referenceTreeList.DependsOn(dependsOn) {
+				fmt.Println(dependency)
+			}
+			return
+		}
+		switch format {
+		case "text":
+			fmt.Print(referenceTreeList)
+		case "dot":
+			fmt.Print(roosa.NewGraph(referenceTreeList.Roots()).DOT())
+		case "json":
+			output, err := roosa.NewGraph(referenceTreeList.Roots()).JSON()
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Println(output)
+		case "mermaid":
+			fmt.Print(roosa.NewGraph(referenceTreeList.Roots()).Mermaid())
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(treeCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// treeCmd.PersistentFlags().String("foo", "", "A help for foo")
+	treeCmd.PersistentFlags().StringVarP(
+		&format,
+		"format",
+		"",
+		"text",
+		"Output format: text, dot, json or mermaid.",
+	)
+	treeCmd.PersistentFlags().StringVarP(
+		&dependsOn,
+		"depends-on",
+		"",
+		"",
+		"List the records depending on this name or address.",
+	)
+	treeCmd.PersistentFlags().StringVarP(
+		&save,
+		"save",
+		"",
+		"",
+		"File to save a snapshot of the reference trees to.",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// treeCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
@@ -0,0 +1,78 @@
+package roosa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dependency is a record resolving, directly or through other records, to
+// a name or address. Chain holds the names in between, from the record to
+// the name or address it depends on.
+type Dependency struct {
+	Name  string
+	Type  string
+	Chain []string
+}
+
+// String returns a line describing d.
+func (d *Dependency) String() string {
+	return fmt.Sprintf("%s %s: %s", d.Name, d.Type, strings.Join(d.Chain, " -> "))
+}
+
+// matchesValue returns true if node has key among the targets out of the
+// list it points to, or among its values if it points to none.
+func matchesValue(node *Node, key string) bool {
+	if len(node.References()) > 0 {
+		for _, reference := range node.External() {
+			if lookupKey(reference.Target) == key {
+				return true
+			}
+		}
+		return false
+	}
+	for _, record := range node.content.ResourceRecords {
+		if lookupKey(*record.Value) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// DependsOn returns the records depending on target, which is either the
+// name of a record, or an address or a name out of the domain records
+// point to. Records reached through several paths are returned once per path.
+func (rtl *ReferenceTreeList) DependsOn(target string) (dependencies []*Dependency) {
+	key := lookupKey(target)
+	var walk func(node *Node, chain []string)
+	walk = func(node *Node, chain []string) {
+		chain = append([]string{node.Name()}, chain...)
+		dependencies = append(dependencies, &Dependency{
+			Name:  node.Name(),
+			Type:  node.Type(),
+			Chain: chain,
+		})
+		for _, child := range node.Children() {
+			walk(child, chain)
+		}
+	}
+	var find func(node *Node)
+	find = func(node *Node) {
+		if matchesValue(node, key) {
+			walk(node, []string{target})
+			return
+		}
+		if lookupKey(node.Name()) == key {
+			for _, child := range node.Children() {
+				walk(child, []string{node.Name()})
+			}
+			return
+		}
+		for _, child := range node.Children() {
+			find(child)
+		}
+	}
+	for _, root := range rtl.Roots() {
+		find(root)
+	}
+	return
+}
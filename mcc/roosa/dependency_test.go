@@ -0,0 +1,64 @@
+package roosa
+
+import (
+	"testing"
+)
+
+var dependsontests = []struct {
+	target       string
+	dependencies []string
+}{
+	{
+		"127.0.0.1",
+		[]string{
+			"multiple-a.example.com. A: multiple-a.example.com. -> 127.0.0.1",
+			"root.example.com. A: root.example.com. -> 127.0.0.1",
+			"root-son-sibling.example.com. CNAME: " +
+				"root-son-sibling.example.com. -> root.example.com. -> 127.0.0.1",
+			"root-son.example.com. CNAME: " +
+				"root-son.example.com. -> root.example.com. -> 127.0.0.1",
+			"root-grandson.example.com. CNAME: root-grandson.example.com. -> " +
+				"root-son.example.com. -> root.example.com. -> 127.0.0.1",
+			"service1.example.com. CNAME: " +
+				"service1.example.com. -> root.example.com. -> 127.0.0.1",
+		},
+	},
+	{
+		"ROOT-SON.example.com",
+		[]string{
+			"root-grandson.example.com. CNAME: " +
+				"root-grandson.example.com. -> root-son.example.com.",
+		},
+	},
+	{
+		"test.example2.com.",
+		[]string{
+			"test.example.com. CNAME: test.example.com. -> test.example2.com.",
+		},
+	},
+	{
+		"10.0.0.1",
+		[]string{},
+	},
+}
+
+func TestDependsOn(t *testing.T) {
+	rtl := NewReferenceTreeList(generateRoute53RRS())
+	for _, tt := range dependsontests {
+		dependencies := rtl.DependsOn(tt.target)
+		if len(dependencies) != len(tt.dependencies) {
+			t.Errorf(
+				"%s: expected %d dependencies, got %v",
+				tt.target,
+				len(tt.dependencies),
+				dependencies,
+			)
+			continue
+		}
+		for i, dependency := range dependencies {
+			if dependency.String() != tt.dependencies[i] {
+				t.Errorf("Expected '%s', got '%s'", tt.dependencies[i], dependency)
+			}
+		}
+	}
+}
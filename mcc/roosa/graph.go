@@ -0,0 +1,199 @@
+package roosa
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Vertex is a record, or a name out of the domain records point to, in a
+// Graph.
+type Vertex struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type,omitempty"`
+	Values    []string `json:"values,omitempty"`
+	Resources []string `json:"resources,omitempty"`
+	External  bool     `json:"external,omitempty"`
+}
+
+// Label returns the text describing v in graphs.
+func (v *Vertex) Label() string {
+	if v.External {
+		return v.Name
+	}
+	label := fmt.Sprintf(
+		"%s\n%s %s",
+		v.Name,
+		v.Type,
+		strings.Join(v.Values, ", "),
+	)
+	for _, resource := range v.Resources {
+		label += "\n" + resource
+	}
+	return label
+}
+
+// Edge is a reference from the vertex identified by From to the one
+// identified by To, with the priority, weight and port of MX and SRV
+// references.
+type Edge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Priority string `json:"priority,omitempty"`
+	Weight   string `json:"weight,omitempty"`
+	Port     string `json:"port,omitempty"`
+}
+
+// newEdge returns the edge from the vertex identified by from to the one
+// identified by to for reference, if any.
+func newEdge(from, to string, reference *Reference) *Edge {
+	edge := &Edge{From: from, To: to}
+	if reference != nil {
+		edge.Priority = reference.Priority
+		edge.Weight = reference.Weight
+		edge.Port = reference.Port
+	}
+	return edge
+}
+
+// Label returns the text describing the attributes of e, if any.
+func (e *Edge) Label() string {
+	reference := &Reference{Priority: e.Priority, Weight: e.Weight, Port: e.Port}
+	return strings.Join(reference.Attributes(), ", ")
+}
+
+// Graph represents the reference trees as vertices and the edges in
+// between them, with records pointing to their targets.
+type Graph struct {
+	Vertices []*Vertex `json:"vertices"`
+	Edges    []*Edge   `json:"edges"`
+	ids      map[*Node]string
+	external map[string]string
+}
+
+// NewGraph builds the Graph of the reference trees starting at roots.
+// Vertices are identified in the order they are found, so the same trees
+// always render the same way.
+func NewGraph(roots []*Node) *Graph {
+	g := &Graph{
+		Vertices: []*Vertex{},
+		Edges:    []*Edge{},
+		ids:      map[*Node]string{},
+		external: map[string]string{},
+	}
+	for _, root := range roots {
+		g.add(root)
+	}
+	return g
+}
+
+// nextID returns the identifier for the next vertex.
+func (g *Graph) nextID() string {
+	return fmt.Sprintf("n%d", len(g.Vertices))
+}
+
+// add adds node and its children, once, returning the node identifier.
+func (g *Graph) add(node *Node) string {
+	if id, ok := g.ids[node]; ok {
+		return id
+	}
+	id := g.nextID()
+	g.ids[node] = id
+	g.Vertices = append(g.Vertices, &Vertex{
+		ID:        id,
+		Name:      node.Name(),
+		Type:      node.Type(),
+		Values:    node.Values(),
+		Resources: node.Resources(),
+	})
+	for _, child := range node.Children() {
+		g.Edges = append(
+			g.Edges,
+			newEdge(g.add(child), id, child.references[node]),
+		)
+	}
+	for _, reference := range node.External() {
+		g.Edges = append(
+			g.Edges,
+			newEdge(id, g.addExternal(reference.Target), reference),
+		)
+	}
+	return id
+}
+
+// addExternal adds a name out of the domain, once, returning its
+// identifier.
+func (g *Graph) addExternal(name string) string {
+	if id, ok := g.external[name]; ok {
+		return id
+	}
+	id := g.nextID()
+	g.external[name] = id
+	g.Vertices = append(g.Vertices, &Vertex{
+		ID:       id,
+		Name:     name,
+		External: true,
+	})
+	return id
+}
+
+// dotQuote returns s as a DOT quoted string.
+func dotQuote(s string) string {
+	s = strings.Replace(s, "\\", "\\\\", -1)
+	s = strings.Replace(s, "\"", "\\\"", -1)
+	return "\"" + strings.Replace(s, "\n", "\\n", -1) + "\""
+}
+
+// DOT returns the graph in Graphviz DOT format.
+func (g *Graph) DOT() string {
+	output := "digraph G {\n\trankdir=LR;\n"
+	for _, vertex := range g.Vertices {
+		attrs := ""
+		if vertex.External {
+			attrs = ", shape=box, style=dashed"
+		}
+		output += fmt.Sprintf(
+			"\t%s [label=%s%s];\n",
+			vertex.ID,
+			dotQuote(vertex.Label()),
+			attrs,
+		)
+	}
+	for _, edge := range g.Edges {
+		attrs := ""
+		if label := edge.Label(); label != "" {
+			attrs = fmt.Sprintf(" [label=%s]", dotQuote(label))
+		}
+		output += fmt.Sprintf("\t%s -> %s%s;\n", edge.From, edge.To, attrs)
+	}
+	return output + "}\n"
+}
+
+// Mermaid returns the graph as a Mermaid flowchart.
+func (g *Graph) Mermaid() string {
+	output := "graph LR\n"
+	for _, vertex := range g.Vertices {
+		label := strings.Replace(vertex.Label(), "\"", "#quot;", -1)
+		label = strings.Replace(label, "\n", "<br/>", -1)
+		output += fmt.Sprintf("  %s[\"%s\"]\n", vertex.ID, label)
+		if vertex.External {
+			output += fmt.Sprintf("  class %s external\n", vertex.ID)
+		}
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if label := edge.Label(); label != "" {
+			arrow = fmt.Sprintf("-->|\"%s\"|", label)
+		}
+		output += fmt.Sprintf("  %s %s %s\n", edge.From, arrow, edge.To)
+	}
+	output += "  classDef external stroke-dasharray: 5 5\n"
+	return output
+}
+
+// JSON returns the graph in JSON format.
+func (g *Graph) JSON() (string, error) {
+	out, err := json.MarshalIndent(g, "", "  ")
+	return string(out), err
+}
@@ -0,0 +1,82 @@
+package roosa
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var sortedOutput = `example.com. A 10.10.10.10
+multiple-a.example.com. A 127.0.0.1, 127.0.0.2, 127.0.0.3
+root.example.com. A 127.0.0.1
+	root-son-sibling.example.com. CNAME root.example.com
+	root-son.example.com. CNAME root.example.com
+		root-grandson.example.com. CNAME root-son.example.com
+	service1.example.com. CNAME root.example.com
+root2.example.com. A 127.0.0.2
+	service1.example.com. CNAME root2.example.com
+test.example.com. CNAME test.example2.com
+`
+
+func TestReferenceTreeListSorted(t *testing.T) {
+	for i := 0; i < 5; i++ {
+		output := NewReferenceTreeList(generateRoute53RRS()).String()
+		if output != sortedOutput {
+			t.Fatalf("Expected:\n%s\nGot:\n%s", sortedOutput, output)
+		}
+	}
+}
+
+func TestGraph(t *testing.T) {
+	rtl := NewReferenceTreeList(generateRoute53RRS())
+	g := NewGraph(rtl.Roots())
+	// 10 records plus the external target
+	if len(g.Vertices) != 11 {
+		t.Errorf("Expected 11 vertices, got %d", len(g.Vertices))
+	}
+	external := g.Vertices[len(g.Vertices)-1]
+	if !external.External || external.Name != "test.example2.com" {
+		t.Errorf("Expected external vertex for test.example2.com, got %v", external)
+	}
+	if len(g.Edges) != 6 {
+		t.Errorf("Expected 6 edges, got %d", len(g.Edges))
+	}
+	again := NewGraph(NewReferenceTreeList(generateRoute53RRS()).Roots())
+	if g.Mermaid() != again.Mermaid() || g.DOT() != again.DOT() {
+		t.Error("Graphs of the same records must render the same")
+	}
+}
+
+func TestGraphFormats(t *testing.T) {
+	g := NewGraph(NewReferenceTreeList(generateRoute53RRS()).Roots())
+	mermaid := g.Mermaid()
+	for _, line := range []string{
+		"graph LR",
+		"  n0[\"example.com.<br/>A 10.10.10.10\"]",
+		"  n10[\"test.example2.com\"]",
+		"  class n10 external",
+		"  n9 --> n10",
+	} {
+		if !strings.Contains(mermaid, line+"\n") {
+			t.Errorf("'%s' should be in output:\n%s", line, mermaid)
+		}
+	}
+	dot := g.DOT()
+	for _, line := range []string{"digraph G", "n9 -> n10", "style=dashed"} {
+		if !strings.Contains(dot, line) {
+			t.Errorf("'%s' should be in output:\n%s", line, dot)
+		}
+	}
+	output, err := g.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &Graph{}
+	if err := json.Unmarshal([]byte(output), decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Vertices) != len(g.Vertices) ||
+		len(decoded.Edges) != len(g.Edges) {
+		t.Errorf("JSON output doesn't match graph:\n%s", output)
+	}
+}
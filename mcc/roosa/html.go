@@ -0,0 +1,206 @@
+package roosa
+
+import (
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+)
+
+// page is the HTML explorer of a Graph, rendering its reference trees as
+// nested collapsible lists. It includes all styles and scripts, so it can
+// be saved and opened without the server.
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>roosa</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; margin: 1em 2em; }
+ul { list-style: none; padding-left: 1.5em; margin: 0; }
+#forest { padding-left: 0; }
+.node { padding: 1px 0; }
+summary { cursor: pointer; }
+.record { border-left: 4px solid #999; padding: 0 4px; }
+.type { font-weight: bold; margin: 0 4px; }
+.values, .resources { color: #555; }
+.resources { font-style: italic; }
+.type-A { border-color: #2a7ab0; }
+.type-AAAA { border-color: #6a3d9a; }
+.type-CNAME { border-color: #33a02c; }
+.type-MX { border-color: #ff7f00; }
+.type-SRV { border-color: #b15928; }
+.type-NS { border-color: #e31a1c; }
+.type-PTR { border-color: #fb9a99; }
+.external { color: #c00; border: 1px dashed #c00; padding: 0 4px; }
+.match > .record .name, .match > details > summary .name { background: #ff0; }
+#toolbar { margin-bottom: 1em; }
+#search { width: 30em; }
+</style>
+</head>
+<body>
+<div id="toolbar">
+<input id="search" type="search" placeholder="Search by name">
+<button id="expand">Expand all</button>
+<button id="collapse">Collapse all</button>
+<span id="count"></span>
+</div>
+<ul id="forest"></ul>
+<script>
+var graph = {{.}};
+var vertices = {}, children = {}, external = {}, parents = {};
+graph.vertices.forEach(function(vertex) {
+	vertices[vertex.id] = vertex;
+	children[vertex.id] = [];
+	external[vertex.id] = [];
+});
+graph.edges.forEach(function(edge) {
+	if (vertices[edge.to].external) {
+		external[edge.from].push(edge);
+	} else {
+		children[edge.to].push(edge);
+		parents[edge.from] = true;
+	}
+});
+
+function text(tag, className, content) {
+	var element = document.createElement(tag);
+	element.className = className;
+	element.textContent = content;
+	return element;
+}
+
+function attributes(edge) {
+	var list = [];
+	["priority", "weight", "port"].forEach(function(name) {
+		if (edge[name]) {
+			list.push(name + " " + edge[name]);
+		}
+	});
+	return list.length ? " (" + list.join(", ") + ")" : "";
+}
+
+function record(vertex, edge) {
+	var span = text("span", "record type-" + vertex.type, "");
+	span.appendChild(text("span", "name", vertex.name));
+	span.appendChild(text("span", "type", vertex.type));
+	span.appendChild(text("span", "values", (vertex.values || []).join(", ")));
+	if (vertex.resources) {
+		span.appendChild(text("span", "resources", " [" + vertex.resources.join("; ") + "]"));
+	}
+	if (edge) {
+		span.appendChild(text("span", "values", attributes(edge)));
+	}
+	return span;
+}
+
+function render(id, edge) {
+	var vertex = vertices[id];
+	var item = document.createElement("li");
+	item.className = "node";
+	item.dataset.name = vertex.name.toLowerCase();
+	if (!children[id].length && !external[id].length) {
+		item.appendChild(record(vertex, edge));
+		return item;
+	}
+	var details = document.createElement("details");
+	details.open = true;
+	var summary = document.createElement("summary");
+	summary.appendChild(record(vertex, edge));
+	details.appendChild(summary);
+	var list = document.createElement("ul");
+	external[id].forEach(function(edge) {
+		var leaf = document.createElement("li");
+		leaf.appendChild(text("span", "external", "→ " + vertices[edge.to].name + attributes(edge)));
+		list.appendChild(leaf);
+	});
+	children[id].forEach(function(edge) {
+		list.appendChild(render(edge.from, edge));
+	});
+	details.appendChild(list);
+	item.appendChild(details);
+	return item;
+}
+
+var forest = document.getElementById("forest");
+graph.vertices.forEach(function(vertex) {
+	if (!vertex.external && !parents[vertex.id]) {
+		forest.appendChild(render(vertex.id));
+	}
+});
+
+function filter(item, query) {
+	var visible = false;
+	var list = item.querySelector(":scope > details > ul");
+	if (list) {
+		Array.prototype.forEach.call(list.children, function(child) {
+			if (child.classList.contains("node") && filter(child, query)) {
+				visible = true;
+			}
+		});
+		if (query && visible) {
+			item.firstChild.open = true;
+		}
+	}
+	var match = query !== "" && item.dataset.name.indexOf(query) >= 0;
+	item.classList.toggle("match", match);
+	visible = visible || match || query === "";
+	item.style.display = visible ? "" : "none";
+	return visible;
+}
+
+function search() {
+	var query = document.getElementById("search").value.trim().toLowerCase();
+	Array.prototype.forEach.call(forest.children, function(item) {
+		filter(item, query);
+	});
+	document.getElementById("count").textContent =
+		query ? forest.querySelectorAll(".match").length + " matches" : "";
+}
+
+function toggle(open) {
+	Array.prototype.forEach.call(forest.querySelectorAll("details"), function(details) {
+		details.open = open;
+	});
+}
+
+document.getElementById("search").addEventListener("input", search);
+document.getElementById("expand").addEventListener("click", function() { toggle(true); });
+document.getElementById("collapse").addEventListener("click", function() { toggle(false); });
+</script>
+</body>
+</html>
+`))
+
+// HTML writes the HTML explorer of g, where trees can be collapsed and
+// searched by name, records are coloured by type, and names out of the
+// domain are highlighted.
+func (g *Graph) HTML(w io.Writer) error {
+	return page.Execute(w, g)
+}
+
+// NewHandler returns an http.Handler serving the HTML explorer of g, and g
+// in JSON format at /graph.json.
+func NewHandler(g *Graph) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graph.json", func(w http.ResponseWriter, r *http.Request) {
+		output, err := g.JSON()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, output)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := g.HTML(w); err != nil {
+			log.Println(err)
+		}
+	})
+	return mux
+}
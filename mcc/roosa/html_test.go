@@ -0,0 +1,59 @@
+package roosa
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	g := NewGraph(NewReferenceTreeList(generateRoute53RRS()).Roots())
+	server := httptest.NewServer(NewHandler(g))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(body)
+	for _, content := range []string{
+		"<input id=\"search\"",
+		"\"name\":\"root-grandson.example.com.\"",
+		"\"name\":\"test.example2.com\",\"external\":true",
+	} {
+		if !strings.Contains(page, content) {
+			t.Errorf("'%s' should be in page:\n%s", content, page)
+		}
+	}
+
+	resp, err = http.Get(server.URL + "/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &Graph{}
+	err = json.NewDecoder(resp.Body).Decode(decoded)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Vertices) != len(g.Vertices) {
+		t.Errorf("Expected %d vertices, got %d", len(g.Vertices), len(decoded.Vertices))
+	}
+
+	resp, err = http.Get(server.URL + "/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected not found, got %d", resp.StatusCode)
+	}
+}
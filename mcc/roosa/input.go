@@ -0,0 +1,90 @@
+package roosa
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/miekg/dns"
+)
+
+// newResourceRecordSets groups records by name and type into record sets,
+// keeping the order they were first found in.
+func newResourceRecordSets(
+	records []dns.RR,
+) (list []*route53.ResourceRecordSet) {
+	sets := map[string]*route53.ResourceRecordSet{}
+	for _, rr := range records {
+		header := rr.Header()
+		typ := dns.TypeToString[header.Rrtype]
+		key := strings.ToLower(header.Name) + " " + typ
+		set, ok := sets[key]
+		if !ok {
+			set = &route53.ResourceRecordSet{
+				Name: aws.String(header.Name),
+				Type: aws.String(typ),
+				TTL:  aws.Int64(int64(header.Ttl)),
+			}
+			sets[key] = set
+			list = append(list, set)
+		}
+		set.ResourceRecords = append(
+			set.ResourceRecords,
+			&route53.ResourceRecord{
+				Value: aws.String(
+					strings.TrimPrefix(rr.String(), header.String()),
+				),
+			},
+		)
+	}
+	return
+}
+
+// ReadZoneFile returns the record sets in an RFC 1035 zone file. Relative
+// names are completed with origin, unless the file sets its own $ORIGIN,
+// and file is the name errors refer to.
+func ReadZoneFile(
+	r io.Reader,
+	origin, file string,
+) ([]*route53.ResourceRecordSet, error) {
+	records := []dns.RR{}
+	parser := dns.NewZoneParser(r, dns.Fqdn(origin), file)
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		records = append(records, rr)
+	}
+	if err := parser.Err(); err != nil {
+		return nil, err
+	}
+	return newResourceRecordSets(records), nil
+}
+
+// ReadJSON returns the record sets in JSON format in r, as
+// `aws route53 list-resource-record-sets` outputs them.
+func ReadJSON(r io.Reader) ([]*route53.ResourceRecordSet, error) {
+	output := route53.ListResourceRecordSetsOutput{}
+	if err := json.NewDecoder(r).Decode(&output); err != nil {
+		return nil, err
+	}
+	return output.ResourceRecordSets, nil
+}
+
+// ReadRecordsFile returns the record sets in a file, either a JSON export
+// in the format `aws route53 list-resource-record-sets` and `got export`
+// output if it has a .json extension, or a zone file otherwise.
+func ReadRecordsFile(
+	path, origin string,
+) ([]*route53.ResourceRecordSet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if filepath.Ext(path) == ".json" {
+		return ReadJSON(file)
+	}
+	return ReadZoneFile(file, origin, path)
+}
@@ -0,0 +1,76 @@
+package roosa
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var zoneFile = `$TTL 300
+@	IN	SOA	ns1 hostmaster 1 7200 3600 1209600 300
+	IN	NS	ns1
+	IN	A	10.0.0.1
+ns1	IN	A	10.0.0.2
+www	IN	CNAME	@
+api	IN	CNAME	lb.example.org.
+`
+
+var zoneFileOutput = `api.example.com. CNAME lb.example.org.
+example.com. A 10.0.0.1
+	www.example.com. CNAME example.com.
+ns1.example.com. A 10.0.0.2
+	example.com. NS ns1.example.com.
+`
+
+var snapshot = `{"ResourceRecordSets": [
+  {"Name": "example.com.", "Type": "A", "TTL": 300,
+   "ResourceRecords": [{"Value": "10.0.0.1"}]},
+  {"Name": "www.example.com.", "Type": "CNAME", "TTL": 300,
+   "ResourceRecords": [{"Value": "example.com"}]}
+]}
+`
+
+func TestReadZoneFile(t *testing.T) {
+	records, err := ReadZoneFile(strings.NewReader(zoneFile), "example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := NewReferenceTreeList(records).String()
+	if output != zoneFileOutput {
+		t.Errorf("Expected:\n%s\nGot:\n%s", zoneFileOutput, output)
+	}
+	_, err = ReadZoneFile(strings.NewReader("www IN A not-an-address\n"), "example.com", "")
+	if err == nil {
+		t.Error("Invalid zone files should fail")
+	}
+}
+
+func TestReadRecordsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "roosa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, content := range map[string]string{
+		"example.com.zone": zoneFile,
+		"example.com.json": snapshot,
+	} {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		records, err := ReadRecordsFile(path, "example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		output := NewReferenceTreeList(records).String()
+		if !strings.Contains(output, "\twww.example.com. CNAME example.com") {
+			t.Errorf("%s not read as expected:\n%s", name, output)
+		}
+	}
+	if _, err := ReadRecordsFile(filepath.Join(dir, "missing"), ""); err == nil {
+		t.Error("Missing files should fail")
+	}
+}
@@ -2,6 +2,7 @@ package roosa
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/route53"
@@ -9,10 +10,40 @@ import (
 
 // Node type represents the reference between data.
 type Node struct {
-	parent   *Node
-	children []*Node
-	content  *route53.ResourceRecordSet
-	indent   int
+	parent    *Node
+	children  []*Node
+	content   *route53.ResourceRecordSet
+	indent    int
+	resources []*Resource
+	// references to the parents of the node, and out of the list
+	references map[*Node]*Reference
+	external   []*Reference
+}
+
+// Reference is a name a record points to, with the priority, weight and
+// port of the reference for MX and SRV records.
+type Reference struct {
+	Target   string
+	Priority string
+	Weight   string
+	Port     string
+}
+
+// Attributes returns the attributes of r, such as "priority 10".
+func (r *Reference) Attributes() (attributes []string) {
+	for _, attribute := range []struct{ name, value string }{
+		{"priority", r.Priority},
+		{"weight", r.Weight},
+		{"port", r.Port},
+	} {
+		if attribute.value != "" {
+			attributes = append(
+				attributes,
+				attribute.name+" "+attribute.value,
+			)
+		}
+	}
+	return
 }
 
 // IsRoot returns true if n is a root node.
@@ -26,20 +57,18 @@ func (n *Node) String() (output string) {
 	for i := 0; i < n.indent; i++ {
 		indents += "\t"
 	}
-	extra := ""
-	l := len(n.content.ResourceRecords)
-	for _, record := range n.content.ResourceRecords[:l-1] {
-		extra += fmt.Sprintf("%v, ", *record.Value)
-	}
-	extra += *n.content.ResourceRecords[l-1].Value
 	output = fmt.Sprintf(
-		"%v%v %v %v\n",
+		"%v%v %v %v",
 		indents,
 		*n.content.Name,
 		*n.content.Type,
-		extra,
+		strings.Join(n.Values(), ", "),
 	)
-	for _, child := range n.children {
+	if len(n.resources) > 0 {
+		output += fmt.Sprintf(" [%v]", strings.Join(n.Resources(), "; "))
+	}
+	output += "\n"
+	for _, child := range sortNodes(n.children) {
 		child.indent = n.indent + 1
 		output += child.String()
 	}
@@ -48,3 +77,103 @@ func (n *Node) String() (output string) {
 	}
 	return
 }
+
+// Name returns the name of the record n represents.
+func (n *Node) Name() string {
+	return *n.content.Name
+}
+
+// Type returns the type of the record n represents.
+func (n *Node) Type() string {
+	return *n.content.Type
+}
+
+// Values returns the values of the record n represents, or its alias
+// target.
+func (n *Node) Values() (values []string) {
+	if n.content.AliasTarget != nil {
+		return []string{"ALIAS " + *n.content.AliasTarget.DNSName}
+	}
+	for _, record := range n.content.ResourceRecords {
+		values = append(values, *record.Value)
+	}
+	return
+}
+
+// Target returns the name the record n represents points to, if it's a
+// CNAME or an alias, or an empty string otherwise.
+func (n *Node) Target() string {
+	switch {
+	case n.content.AliasTarget != nil:
+		return *n.content.AliasTarget.DNSName
+	case n.Type() == "CNAME" && len(n.content.ResourceRecords) > 0:
+		return *n.content.ResourceRecords[0].Value
+	}
+	return ""
+}
+
+// References returns the names the record n represents points to: the
+// target of CNAMEs and aliases, and the hosts of MX, SRV, NS and PTR
+// records.
+func (n *Node) References() (references []*Reference) {
+	if target := n.Target(); target != "" {
+		return []*Reference{{Target: target}}
+	}
+	for _, record := range n.content.ResourceRecords {
+		fields := strings.Fields(*record.Value)
+		switch {
+		case n.Type() == "MX" && len(fields) == 2:
+			references = append(references, &Reference{
+				Target:   fields[1],
+				Priority: fields[0],
+			})
+		case n.Type() == "SRV" && len(fields) == 4:
+			references = append(references, &Reference{
+				Target:   fields[3],
+				Priority: fields[0],
+				Weight:   fields[1],
+				Port:     fields[2],
+			})
+		case (n.Type() == "NS" || n.Type() == "PTR") && len(fields) == 1:
+			references = append(references, &Reference{Target: fields[0]})
+		}
+	}
+	return
+}
+
+// External returns the references of n to names out of the list.
+func (n *Node) External() []*Reference {
+	return n.external
+}
+
+// Resources returns the AWS resources behind the record n represents.
+func (n *Node) Resources() (resources []string) {
+	for _, resource := range n.resources {
+		resources = append(resources, resource.String())
+	}
+	return
+}
+
+// Children returns the nodes referencing n, sorted.
+func (n *Node) Children() []*Node {
+	return sortNodes(n.children)
+}
+
+// key returns a string identifying n by its record, to sort nodes by.
+func (n *Node) key() string {
+	return fmt.Sprintf(
+		"%s %s %s",
+		strings.ToLower(n.Name()),
+		n.Type(),
+		strings.Join(n.Values(), ","),
+	)
+}
+
+// sortNodes returns a copy of nodes sorted by name, type and values.
+func sortNodes(nodes []*Node) []*Node {
+	sorted := append([]*Node{}, nodes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].key() < sorted[j].key()
+	})
+	return sorted
+}
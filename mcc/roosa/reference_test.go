@@ -0,0 +1,90 @@
+package roosa
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+func newRecord(name, typ string, values ...string) *route53.ResourceRecordSet {
+	record := &route53.ResourceRecordSet{
+		Name: aws.String(name),
+		Type: aws.String(typ),
+	}
+	for _, value := range values {
+		record.ResourceRecords = append(
+			record.ResourceRecords,
+			&route53.ResourceRecord{Value: aws.String(value)},
+		)
+	}
+	return record
+}
+
+func generateReferenceRRS() []*route53.ResourceRecordSet {
+	return []*route53.ResourceRecordSet{
+		newRecord("mail.example.com.", "A", "10.0.0.1"),
+		newRecord("sip.example.com.", "A", "10.0.0.2"),
+		newRecord("example.com.", "MX", "10 mail.example.com.", "20 mx.example.org."),
+		newRecord("_sip._tcp.example.com.", "SRV", "10 5 5060 sip.example.com."),
+		newRecord("example.com.", "NS", "ns-1.awsdns-01.org."),
+		newRecord("1.0.0.10.in-addr.arpa.", "PTR", "mail.example.com."),
+		newRecord("3.0.0.10.in-addr.arpa.", "PTR", "sip.example.com."),
+	}
+}
+
+var referenceOutput = `example.com. NS ns-1.awsdns-01.org.
+mail.example.com. A 10.0.0.1
+	example.com. MX 10 mail.example.com., 20 mx.example.org.
+sip.example.com. A 10.0.0.2
+	_sip._tcp.example.com. SRV 10 5 5060 sip.example.com.
+`
+
+var ptrOutput = `3.0.0.10.in-addr.arpa. PTR sip.example.com.
+example.com. NS ns-1.awsdns-01.org.
+mail.example.com. A 10.0.0.1
+	1.0.0.10.in-addr.arpa. PTR mail.example.com.
+	example.com. MX 10 mail.example.com., 20 mx.example.org.
+sip.example.com. A 10.0.0.2
+	_sip._tcp.example.com. SRV 10 5 5060 sip.example.com.
+`
+
+func TestReferences(t *testing.T) {
+	rtl := NewReferenceTreeList(generateReferenceRRS())
+	if output := rtl.String(); output != referenceOutput {
+		t.Errorf("Expected:\n%s\nGot:\n%s", referenceOutput, output)
+	}
+	if len(rtl.Warnings()) != 0 {
+		t.Errorf("Unexpected warnings %v", rtl.Warnings())
+	}
+	dot := NewGraph(rtl.Roots()).DOT()
+	for _, line := range []string{
+		"n3 -> n2 [label=\"priority 10\"];",
+		"n3 -> n4 [label=\"priority 20\"];",
+		"n6 -> n5 [label=\"priority 10, weight 5, port 5060\"];",
+		"n0 -> n1;",
+	} {
+		if !strings.Contains(dot, line) {
+			t.Errorf("'%s' should be in output:\n%s", line, dot)
+		}
+	}
+	mermaid := NewGraph(rtl.Roots()).Mermaid()
+	if !strings.Contains(mermaid, "  n3 -->|\"priority 10\"| n2\n") {
+		t.Errorf("Edge attributes should be in output:\n%s", mermaid)
+	}
+}
+
+func TestMatchPTR(t *testing.T) {
+	rtl := NewReferenceTreeList(generateReferenceRRS())
+	rtl.MatchPTR = true
+	if output := rtl.String(); output != ptrOutput {
+		t.Errorf("Expected:\n%s\nGot:\n%s", ptrOutput, output)
+	}
+	warnings := rtl.Warnings()
+	expected := "ptr: 3.0.0.10.in-addr.arpa. points to sip.example.com., " +
+		"which doesn't resolve back to it"
+	if len(warnings) != 1 || warnings[0].String() != expected {
+		t.Errorf("Expected '%s', got %v", expected, warnings)
+	}
+}
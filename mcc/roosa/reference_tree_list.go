@@ -3,11 +3,13 @@ package roosa
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/miekg/dns"
 )
 
 // GetResourceRecordSet returns a slice containing all responses for specified
@@ -39,18 +41,88 @@ func GetResourceRecordSet(
 	return
 }
 
-// GetZoneID returns a string containing the ZoneID for use in further API
-// actions
-func GetZoneID(zoneName string, svc route53iface.Route53API) (zoneID string) {
+// GetZoneID returns the ID of the only hosted zone named zoneName, or an
+// error if there's none or several of them.
+func GetZoneID(
+	zoneName string,
+	svc route53iface.Route53API,
+) (zoneID string, err error) {
+	ids := []string{}
 	params := &route53.ListHostedZonesByNameInput{
 		DNSName:  aws.String(zoneName),
 		MaxItems: aws.String("100"),
 	}
-	resp, err := svc.ListHostedZonesByName(params)
-	if err != nil {
-		log.Println(err.Error())
+	for {
+		var resp *route53.ListHostedZonesByNameOutput
+		resp, err = svc.ListHostedZonesByName(params)
+		if err != nil {
+			return
+		}
+		last := true
+		for _, zone := range resp.HostedZones {
+			if !strings.EqualFold(
+				strings.TrimSuffix(*zone.Name, "."),
+				strings.TrimSuffix(zoneName, "."),
+			) {
+				break
+			}
+			last = false
+			ids = append(ids, *zone.Id)
+		}
+		// Results are sorted by name, so there's no need to go on once a
+		// different name is found.
+		if last || !aws.BoolValue(resp.IsTruncated) {
+			break
+		}
+		params.DNSName = resp.NextDNSName
+		params.HostedZoneId = resp.NextHostedZoneId
+	}
+	switch len(ids) {
+	case 0:
+		err = fmt.Errorf("No hosted zone found for %s", zoneName)
+	case 1:
+		zoneID = ids[0]
+	default:
+		err = fmt.Errorf(
+			"Several hosted zones found for %s: %s",
+			zoneName,
+			strings.Join(ids, ", "),
+		)
+	}
+	return
+}
+
+// ListHostedZones returns all the hosted zones in the account. It may
+// issue more than one request as each returns a fixed amount of entries
+// at most.
+func ListHostedZones(
+	svc route53iface.Route53API,
+) (zones []*route53.HostedZone) {
+	params := &route53.ListHostedZonesInput{}
+	for respIsTruncated := true; respIsTruncated; {
+		resp, err := svc.ListHostedZones(params)
+		if err != nil {
+			panic(err)
+		}
+		params.Marker = resp.NextMarker
+		respIsTruncated = *resp.IsTruncated
+		zones = append(zones, resp.HostedZones...)
+	}
+	return
+}
+
+// GetZonesResourceRecordSets returns the record sets of all the hosted
+// zones identified by zoneIDs, in a single list.
+func GetZonesResourceRecordSets(
+	zoneIDs []string,
+	svc route53iface.Route53API,
+) (resourceRecordSet []*route53.ResourceRecordSet) {
+	for _, zoneID := range zoneIDs {
+		resourceRecordSet = append(
+			resourceRecordSet,
+			GetResourceRecordSet(zoneID, svc)...,
+		)
 	}
-	zoneID = *resp.HostedZones[0].Id
 	return
 }
 
@@ -73,24 +145,41 @@ func FilterResourceRecords(
 }
 
 // ReferenceTreeList is a type representing the reference trees for a list of
-// DNS records, explicitly A, AAAA, and CNAME records.
+// DNS records, explicitly A, AAAA, CNAME, MX, SRV, NS and PTR records.
 type ReferenceTreeList struct {
-	records []*route53.ResourceRecordSet
-	lookup  map[string][]*Node
+	// MaxDepth is the number of references from a record to its root
+	// above which a warning is issued, if positive.
+	MaxDepth int
+	// MatchPTR includes PTR records, which become children of the address
+	// records resolving to the address they're named after.
+	MatchPTR bool
+	records  []*route53.ResourceRecordSet
+	lookup   map[string][]*Node
+	warnings []*Warning
 }
 
+// DefaultMaxDepth is the MaxDepth of new ReferenceTreeLists.
+const DefaultMaxDepth = 5
+
 var recordTypes = []string{
 	"A",
 	"AAAA",
 	"CNAME",
+	"MX",
+	"SRV",
+	"NS",
+	"PTR",
 }
 
 // NewReferenceTreeList is the constructor for ReferenceTreeList. It filters
-// A, AAAA, and CNAME records from `records` argument.
+// A, AAAA, CNAME, MX, SRV, NS and PTR records, aliases included, from
+// `records` argument, which may hold the records of several zones to
+// resolve references in between them.
 func NewReferenceTreeList(
 	records []*route53.ResourceRecordSet,
 ) *ReferenceTreeList {
 	return &ReferenceTreeList{
+		MaxDepth: DefaultMaxDepth,
 		records: FilterResourceRecords(
 			records,
 			recordTypes,
@@ -119,79 +208,359 @@ func (rtl *ReferenceTreeList) String() (output string) {
 		rtl.GetReferenceTrees()
 	}
 	output = ""
-	for _, tree := range rtl.lookup {
-		for _, node := range tree {
-			output += fmt.Sprintf("%v\n", node)
-		}
+	for _, node := range rtl.Roots() {
+		output += fmt.Sprintf("%v\n", node)
 	}
 	return
 }
 
+// Roots returns the root nodes of the reference trees, sorted by name,
+// type and values.
+func (rtl *ReferenceTreeList) Roots() (roots []*Node) {
+	if rtl.lookup == nil {
+		rtl.GetReferenceTrees()
+	}
+	for _, tree := range rtl.lookup {
+		roots = append(roots, tree...)
+	}
+	return sortNodes(roots)
+}
+
 // fill fills the referral lookup table with the base records.
 func (rtl *ReferenceTreeList) fill() {
 	rtl.lookup = map[string][]*Node{}
 	for _, val := range rtl.records {
+		if *val.Type == "PTR" && !rtl.MatchPTR {
+			continue
+		}
 		node := &Node{
 			content: val,
 		}
-		name := strings.TrimSuffix(*val.Name, ".")
+		name := lookupKey(*val.Name)
 		rtl.lookup[name] = append(rtl.lookup[name], node)
 	}
 	log.Printf("Added %d records to Lookup\n", len(rtl.lookup))
 	return
 }
 
+// lookupKey returns the key of name in the referral lookup table.
+func lookupKey(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
 // clean removes non-root elements from the referral lookup table.
 func (rtl *ReferenceTreeList) clean() {
 	for name, nodes := range rtl.lookup {
+		roots := []*Node{}
 		for _, node := range nodes {
-			if !node.IsRoot() {
-				delete(rtl.lookup, name)
+			if node.IsRoot() {
+				roots = append(roots, node)
 			}
 		}
+		if len(roots) > 0 {
+			rtl.lookup[name] = roots
+		} else {
+			delete(rtl.lookup, name)
+		}
 	}
 }
 
+// addressTypes are the types of the records names resolve to addresses
+// with, which other records may point to.
+var addressTypes = map[string]bool{
+	"A":     true,
+	"AAAA":  true,
+	"CNAME": true,
+}
+
+// parents returns the nodes node points to with reference. Aliases only
+// point to records of their own type, and PTR records to the address
+// records resolving to the address they're named after, while other
+// records point to any address record.
+func (rtl *ReferenceTreeList) parents(
+	node *Node,
+	reference *Reference,
+) (parents []*Node) {
+	for _, parent := range rtl.lookup[lookupKey(reference.Target)] {
+		switch {
+		case parent == node || !addressTypes[parent.Type()]:
+			continue
+		case node.content.AliasTarget != nil && parent.Type() != node.Type():
+			continue
+		case node.Type() == "PTR" && !resolvesTo(parent, node.Name()):
+			continue
+		}
+		parents = append(parents, parent)
+	}
+	return
+}
+
+// resolvesTo returns true if node has an address which reverse name is
+// arpa.
+func resolvesTo(node *Node, arpa string) bool {
+	for _, record := range node.content.ResourceRecords {
+		reverse, err := dns.ReverseAddr(*record.Value)
+		if err == nil && lookupKey(reverse) == lookupKey(arpa) {
+			return true
+		}
+	}
+	return false
+}
+
 // compact modifies the referral lookup table finding children and
-// roots, and relating these appropriately.
+// roots, and relating these appropriately. Records pointing to records in
+// the list, even from other zones, become their children.
+// References closing a cycle are reported instead of followed, so the
+// trees remain finite.
 func (rtl *ReferenceTreeList) compact() {
+	rtl.warnings = nil
+	rtl.checkParents()
+	state := map[*Node]int{}
+	cyclic := map[*Node]bool{}
+	for _, node := range rtl.nodes() {
+		rtl.link(node, state, []*Node{}, cyclic)
+	}
+	rtl.clean()
+	// Records only referencing each other are not trees, but the records
+	// referencing them become roots of their own
+	dropped := []*Node{}
 	for name, nodes := range rtl.lookup {
+		roots := []*Node{}
 		for _, node := range nodes {
-			value := *node.content.ResourceRecords[0].Value
-			if *node.content.Type == "CNAME" {
-				if parents, ok := rtl.lookup[value]; ok {
-					for _, parent := range parents {
-						log.Printf(
-							"%v (%v) has Parent %v\n",
-							name,
-							value,
-							*parent.content.Name,
-						)
-						parent.children = append(
-							parent.children,
-							node,
-						)
-						node.parent = parent
-					}
-				} else {
-					log.Printf(
-						"%v (%v) is Out of domain\n",
-						name,
-						value,
-					)
-				}
+			if cyclic[node] {
+				dropped = append(dropped, node)
 			} else {
-				log.Printf(
-					"%v (%v) is Root\n",
-					name,
-					value,
-				)
+				roots = append(roots, node)
 			}
 		}
+		if len(roots) > 0 {
+			rtl.lookup[name] = roots
+		} else {
+			delete(rtl.lookup, name)
+		}
+	}
+	kept := map[*Node]bool{}
+	for _, node := range rtl.treeNodes() {
+		kept[node] = true
+	}
+	orphans := []*Node{}
+	for _, node := range dropped {
+		orphans = append(orphans, detach(node, cyclic, kept)...)
+	}
+	for _, node := range orphans {
+		name := lookupKey(node.Name())
+		rtl.lookup[name] = append(rtl.lookup[name], node)
 	}
-	rtl.clean()
 	log.Printf(
 		"Cleared children, %d records left in Lookup\n",
 		len(rtl.lookup),
 	)
+	if rtl.MaxDepth > 0 {
+		for _, root := range rtl.Roots() {
+			rtl.checkDepth(root, []*Node{})
+		}
+	}
+}
+
+// detach removes node, which is in a cycle, from the trees, together
+// with the cyclic nodes under it which aren't in the kept trees, and
+// returns the nodes referencing them left without parents. Their
+// references to removed nodes are kept as external, as they don't lead
+// to any root.
+func detach(node *Node, cyclic, kept map[*Node]bool) (orphans []*Node) {
+	for _, child := range node.Children() {
+		reference := child.references[node]
+		delete(child.references, node)
+		if child.parent == node {
+			child.parent = nil
+			parents := []*Node{}
+			for parent := range child.references {
+				parents = append(parents, parent)
+			}
+			if len(parents) > 0 {
+				child.parent = sortNodes(parents)[0]
+			}
+		}
+		switch {
+		case kept[child]:
+		case cyclic[child]:
+			orphans = append(orphans, detach(child, cyclic, kept)...)
+		default:
+			child.external = append(child.external, reference)
+			if child.IsRoot() {
+				orphans = append(orphans, child)
+			}
+		}
+	}
+	node.children = nil
+	return
+}
+
+// treeNodes returns the nodes in the reference trees, once, parents
+// first.
+func (rtl *ReferenceTreeList) treeNodes() (nodes []*Node) {
+	seen := map[*Node]bool{}
+	var walk func(node *Node)
+	walk = func(node *Node) {
+		if seen[node] {
+			return
+		}
+		seen[node] = true
+		nodes = append(nodes, node)
+		for _, child := range node.Children() {
+			walk(child)
+		}
+	}
+	for _, root := range rtl.Roots() {
+		walk(root)
+	}
+	return
+}
+
+// nodes returns all the nodes in the referral lookup table, sorted.
+func (rtl *ReferenceTreeList) nodes() (nodes []*Node) {
+	for _, tree := range rtl.lookup {
+		nodes = append(nodes, tree...)
+	}
+	return sortNodes(nodes)
+}
+
+// Node states while linking.
+const (
+	linking = iota + 1
+	linked
+)
+
+// link relates node to its parents, linking these first. stack holds the
+// nodes being linked, which reference each other in order, to detect
+// cycles, marking the nodes in them as cyclic.
+func (rtl *ReferenceTreeList) link(
+	node *Node,
+	state map[*Node]int,
+	stack []*Node,
+	cyclic map[*Node]bool,
+) {
+	if state[node] != 0 {
+		return
+	}
+	state[node] = linking
+	stack = append(stack, node)
+	defer func() { state[node] = linked }()
+	references := node.References()
+	if len(references) == 0 {
+		log.Printf(
+			"%v (%v) is Root\n",
+			node.Name(),
+			strings.Join(node.Values(), ", "),
+		)
+		return
+	}
+	node.references = map[*Node]*Reference{}
+	node.external = nil
+	for _, reference := range references {
+		value := reference.Target
+		parents := rtl.parents(node, reference)
+		if len(parents) == 0 {
+			log.Printf("%v (%v) is Out of domain\n", node.Name(), value)
+			node.external = append(node.external, reference)
+			if node.Type() == "PTR" && len(rtl.lookup[lookupKey(value)]) > 0 {
+				rtl.warn(PTRMismatch, []string{node.Name(), value})
+			}
+			continue
+		}
+		for _, parent := range parents {
+			if _, ok := node.references[parent]; ok {
+				continue
+			}
+			if state[parent] == linking {
+				chain := []string{}
+				for i := len(stack) - 1; i >= 0; i-- {
+					chain = append([]string{stack[i].Name()}, chain...)
+					cyclic[stack[i]] = true
+					if stack[i] == parent {
+						break
+					}
+				}
+				rtl.warn(Cycle, append(chain, parent.Name()))
+				continue
+			}
+			rtl.link(parent, state, stack, cyclic)
+			log.Printf(
+				"%v (%v) has Parent %v\n",
+				node.Name(),
+				value,
+				parent.Name(),
+			)
+			parent.children = append(parent.children, node)
+			node.parent = parent
+			node.references[parent] = reference
+		}
+	}
+}
+
+// checkParents warns about names with records of the same type pointing
+// to several records in the list.
+func (rtl *ReferenceTreeList) checkParents() {
+	for _, node := range rtl.nodes() {
+		if node.Target() == "" {
+			continue
+		}
+		targets := map[string]bool{}
+		for _, sibling := range rtl.lookup[lookupKey(node.Name())] {
+			target := sibling.Target()
+			if sibling.Type() == node.Type() && target != "" &&
+				len(rtl.parents(sibling, &Reference{Target: target})) > 0 {
+				targets[target] = true
+			}
+		}
+		if len(targets) < 2 || rtl.warned(MultipleParents, node.Name()) {
+			continue
+		}
+		chain := []string{node.Name()}
+		for target := range targets {
+			chain = append(chain, target)
+		}
+		sort.Strings(chain[1:])
+		rtl.warn(MultipleParents, chain)
+	}
+}
+
+// checkDepth warns about the leaves of node referencing their root through
+// more than MaxDepth references. stack holds the ancestors of node.
+func (rtl *ReferenceTreeList) checkDepth(node *Node, stack []*Node) {
+	stack = append(stack, node)
+	children := node.Children()
+	if len(children) == 0 && len(stack)-1 > rtl.MaxDepth {
+		chain := []string{}
+		for i := len(stack) - 1; i >= 0; i-- {
+			chain = append(chain, stack[i].Name())
+		}
+		rtl.warn(Depth, chain)
+	}
+	for _, child := range children {
+		rtl.checkDepth(child, stack)
+	}
+}
+
+// warn adds a warning of kind about the chain of names.
+func (rtl *ReferenceTreeList) warn(kind string, chain []string) {
+	rtl.warnings = append(rtl.warnings, &Warning{Kind: kind, Chain: chain})
+}
+
+// warned returns true if there is a warning of kind about name already.
+func (rtl *ReferenceTreeList) warned(kind, name string) bool {
+	for _, warning := range rtl.warnings {
+		if warning.Kind == kind && warning.Chain[0] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Warnings returns the problems found building the reference trees.
+func (rtl *ReferenceTreeList) Warnings() []*Warning {
+	if rtl.lookup == nil {
+		rtl.GetReferenceTrees()
+	}
+	return rtl.warnings
 }
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/route53/route53iface"
 )
@@ -106,6 +107,10 @@ var hostedZone = route53.HostedZone{
 func (m *mockRoute53Client) ListHostedZonesByName(
 	params *route53.ListHostedZonesByNameInput,
 ) (out *route53.ListHostedZonesByNameOutput, err error) {
+	if *params.DNSName == "error" {
+		err = fmt.Errorf("Rate exceeded")
+		return
+	}
 	hostedZone.Id = &zoneName
 	hostedZone.Name = &zoneName
 	out = &route53.ListHostedZonesByNameOutput{
@@ -198,12 +203,18 @@ func TestGetZoneID(t *testing.T) {
 	mockSvc := &mockRoute53Client{}
 	for _, s := range grrstest {
 		t.Run(s, func(t *testing.T) {
-			out := GetZoneID(s, mockSvc)
-			if out != s {
+			out, err := GetZoneID(s, mockSvc)
+			if err != nil || out != s {
 				t.Error("Response doesn't match")
 			}
 		})
 	}
+	// Zones are listed from the name given, so others may follow it
+	for _, s := range []string{"missing", "error"} {
+		if _, err := GetZoneID(s, mockSvc); err == nil {
+			t.Errorf("Getting the ID of %s should fail", s)
+		}
+	}
 }
 
 var recordsContents = []string{
@@ -272,3 +283,83 @@ func TestReferenceTreeListString(t *testing.T) {
 		)
 	}
 }
+
+func (m *mockRoute53Client) ListHostedZones(
+	params *route53.ListHostedZonesInput,
+) (out *route53.ListHostedZonesOutput, err error) {
+	hostedZone.Id = &zoneName
+	hostedZone.Name = &zoneName
+	out = &route53.ListHostedZonesOutput{
+		IsTruncated: &fals,
+		MaxItems:    &hundred,
+		HostedZones: []*route53.HostedZone{&hostedZone},
+	}
+	return
+}
+
+func TestListHostedZones(t *testing.T) {
+	mockSvc := &mockRoute53Client{}
+	zones := ListHostedZones(mockSvc)
+	if len(zones) != 1 || *zones[0].Id != zoneName {
+		t.Error("Response doesn't match")
+	}
+	out := GetZonesResourceRecordSets([]string{"one", "two"}, mockSvc)
+	if len(out) != 2*len(ResourceRecordSetList) {
+		t.Error("Records of all zones should be returned")
+	}
+}
+
+func newAlias(name, typ, target string) *route53.ResourceRecordSet {
+	return &route53.ResourceRecordSet{
+		Name:        aws.String(name),
+		Type:        aws.String(typ),
+		AliasTarget: &route53.AliasTarget{DNSName: aws.String(target)},
+	}
+}
+
+var crossZoneOutput = `example.com. A ALIAS dualstack.lb-1.us-east-1.elb.amazonaws.com.
+	www.example.com. A ALIAS example.com.
+		www.example.org. CNAME www.example.com
+example.com. AAAA ::1
+	www.example.com. AAAA ALIAS example.com.
+		www.example.org. CNAME www.example.com
+`
+
+func TestReferenceTreeListCrossZone(t *testing.T) {
+	ipv6 := "::1"
+	target := "www.example.com"
+	records := []*route53.ResourceRecordSet{
+		newAlias("example.com.", "A", "dualstack.lb-1.us-east-1.elb.amazonaws.com."),
+		{
+			Name: aws.String("example.com."),
+			Type: aws.String("AAAA"),
+			ResourceRecords: []*route53.ResourceRecord{
+				{Value: &ipv6},
+			},
+		},
+		newAlias("www.example.com.", "A", "example.com."),
+		newAlias("www.example.com.", "AAAA", "example.com."),
+		// From another zone
+		{
+			Name: aws.String("www.example.org."),
+			Type: aws.String("CNAME"),
+			ResourceRecords: []*route53.ResourceRecord{
+				{Value: &target},
+			},
+		},
+	}
+	rtl := NewReferenceTreeList(records)
+	if output := rtl.String(); output != crossZoneOutput {
+		t.Errorf("Expected:\n%s\nGot:\n%s", crossZoneOutput, output)
+	}
+	externals := []string{}
+	for _, vertex := range NewGraph(rtl.Roots()).Vertices {
+		if vertex.External {
+			externals = append(externals, vertex.Name)
+		}
+	}
+	if len(externals) != 1 ||
+		externals[0] != "dualstack.lb-1.us-east-1.elb.amazonaws.com." {
+		t.Errorf("Only the alias target should be external, got %v", externals)
+	}
+}
@@ -0,0 +1,253 @@
+package roosa
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudfront/cloudfrontiface"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
+
+// Resource is an AWS resource records may point to.
+type Resource struct {
+	Type string
+	ID   string
+	Name string
+}
+
+// String returns the type, identifier and name of r.
+func (r *Resource) String() string {
+	if r.Name == "" {
+		return fmt.Sprintf("%s %s", r.Type, r.ID)
+	}
+	return fmt.Sprintf("%s %s (%s)", r.Type, r.ID, r.Name)
+}
+
+// Inventory holds the resources in an account by the addresses and
+// hostnames they're reachable at.
+type Inventory struct {
+	Addresses map[string][]*Resource
+	Hostnames map[string][]*Resource
+}
+
+// NewInventory is the constructor for an empty Inventory.
+func NewInventory() *Inventory {
+	return &Inventory{
+		Addresses: map[string][]*Resource{},
+		Hostnames: map[string][]*Resource{},
+	}
+}
+
+// hostnameKey returns the key of hostname in the inventory. Dual stack
+// load balancer names Route53 aliases point to are keyed as the load
+// balancer name.
+func hostnameKey(hostname string) string {
+	return strings.TrimPrefix(lookupKey(hostname), "dualstack.")
+}
+
+// addAddress adds resource as reachable at address, if any.
+func (i *Inventory) addAddress(address *string, resource *Resource) {
+	if address != nil && *address != "" {
+		i.Addresses[*address] = append(i.Addresses[*address], resource)
+	}
+}
+
+// addHostname adds resource as reachable at hostname, if any.
+func (i *Inventory) addHostname(hostname *string, resource *Resource) {
+	if hostname != nil && *hostname != "" {
+		key := hostnameKey(*hostname)
+		i.Hostnames[key] = append(i.Hostnames[key], resource)
+	}
+}
+
+// AddEC2 adds the EC2 instances and Elastic IP addresses in the region.
+func (i *Inventory) AddEC2(svc ec2iface.EC2API) {
+	err := svc.DescribeInstancesPages(
+		&ec2.DescribeInstancesInput{},
+		func(page *ec2.DescribeInstancesOutput, last bool) bool {
+			for _, reservation := range page.Reservations {
+				for _, instance := range reservation.Instances {
+					resource := &Resource{
+						Type: "EC2 instance",
+						ID:   aws.StringValue(instance.InstanceId),
+					}
+					for _, tag := range instance.Tags {
+						if aws.StringValue(tag.Key) == "Name" {
+							resource.Name = aws.StringValue(tag.Value)
+						}
+					}
+					i.addAddress(instance.PublicIpAddress, resource)
+					i.addAddress(instance.PrivateIpAddress, resource)
+					i.addHostname(instance.PublicDnsName, resource)
+					i.addHostname(instance.PrivateDnsName, resource)
+				}
+			}
+			return true
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+	resp, err := svc.DescribeAddresses(&ec2.DescribeAddressesInput{})
+	if err != nil {
+		panic(err)
+	}
+	for _, address := range resp.Addresses {
+		// Associated addresses are already known as their instance's
+		if _, ok := i.Addresses[aws.StringValue(address.PublicIp)]; ok {
+			continue
+		}
+		i.addAddress(address.PublicIp, &Resource{
+			Type: "Elastic IP",
+			ID:   aws.StringValue(address.AllocationId),
+		})
+	}
+}
+
+// AddELB adds the classic load balancers in the region.
+func (i *Inventory) AddELB(svc elbiface.ELBAPI) {
+	err := svc.DescribeLoadBalancersPages(
+		&elb.DescribeLoadBalancersInput{},
+		func(page *elb.DescribeLoadBalancersOutput, last bool) bool {
+			for _, lb := range page.LoadBalancerDescriptions {
+				i.addHostname(lb.DNSName, &Resource{
+					Type: "ELB",
+					ID:   aws.StringValue(lb.LoadBalancerName),
+				})
+			}
+			return true
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AddELBv2 adds the application and network load balancers in the region.
+func (i *Inventory) AddELBv2(svc elbv2iface.ELBV2API) {
+	err := svc.DescribeLoadBalancersPages(
+		&elbv2.DescribeLoadBalancersInput{},
+		func(page *elbv2.DescribeLoadBalancersOutput, last bool) bool {
+			for _, lb := range page.LoadBalancers {
+				i.addHostname(lb.DNSName, &Resource{
+					Type: strings.ToUpper(aws.StringValue(lb.Type)) + " LB",
+					ID:   aws.StringValue(lb.LoadBalancerName),
+				})
+			}
+			return true
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AddRDS adds the RDS instance and cluster endpoints in the region.
+func (i *Inventory) AddRDS(svc rdsiface.RDSAPI) {
+	err := svc.DescribeDBInstancesPages(
+		&rds.DescribeDBInstancesInput{},
+		func(page *rds.DescribeDBInstancesOutput, last bool) bool {
+			for _, db := range page.DBInstances {
+				if db.Endpoint == nil {
+					continue
+				}
+				i.addHostname(db.Endpoint.Address, &Resource{
+					Type: "RDS instance",
+					ID:   aws.StringValue(db.DBInstanceIdentifier),
+				})
+			}
+			return true
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+	err = svc.DescribeDBClustersPages(
+		&rds.DescribeDBClustersInput{},
+		func(page *rds.DescribeDBClustersOutput, last bool) bool {
+			for _, cluster := range page.DBClusters {
+				resource := &Resource{
+					Type: "RDS cluster",
+					ID:   aws.StringValue(cluster.DBClusterIdentifier),
+				}
+				i.addHostname(cluster.Endpoint, resource)
+				i.addHostname(cluster.ReaderEndpoint, resource)
+			}
+			return true
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AddCloudFront adds the CloudFront distributions in the account.
+func (i *Inventory) AddCloudFront(svc cloudfrontiface.CloudFrontAPI) {
+	err := svc.ListDistributionsPages(
+		&cloudfront.ListDistributionsInput{},
+		func(page *cloudfront.ListDistributionsOutput, last bool) bool {
+			for _, distribution := range page.DistributionList.Items {
+				resource := &Resource{
+					Type: "CloudFront",
+					ID:   aws.StringValue(distribution.Id),
+				}
+				if distribution.Comment != nil {
+					resource.Name = *distribution.Comment
+				}
+				i.addHostname(distribution.DomainName, resource)
+			}
+			return true
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// isPublic returns true if address is a public IP address.
+func isPublic(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return !(ip4[0] == 10 ||
+			(ip4[0] == 172 && ip4[1]&0xf0 == 16) ||
+			(ip4[0] == 192 && ip4[1] == 168) ||
+			(ip4[0] == 100 && ip4[1]&0xc0 == 64))
+	}
+	// Unique local addresses
+	return ip[0]&0xfe != 0xfc
+}
+
+// MapResources links the roots of the reference trees to the resources in
+// inventory behind them, and warns about roots pointing to public
+// addresses not in it, which are no longer owned.
+func (rtl *ReferenceTreeList) MapResources(inventory *Inventory) {
+	for _, root := range rtl.Roots() {
+		root.resources = nil
+		if target := root.Target(); target != "" {
+			root.resources = inventory.Hostnames[hostnameKey(target)]
+			continue
+		}
+		for _, record := range root.content.ResourceRecords {
+			address := *record.Value
+			resources, ok := inventory.Addresses[address]
+			if !ok && isPublic(address) {
+				rtl.warn(NotOwned, []string{root.Name(), address})
+			}
+			root.resources = append(root.resources, resources...)
+		}
+	}
+}
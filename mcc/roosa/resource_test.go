@@ -0,0 +1,221 @@
+package roosa
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/cloudfront/cloudfrontiface"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+type mockEC2Client struct {
+	ec2iface.EC2API
+}
+
+func (m *mockEC2Client) DescribeInstancesPages(
+	params *ec2.DescribeInstancesInput,
+	fn func(*ec2.DescribeInstancesOutput, bool) bool,
+) error {
+	fn(&ec2.DescribeInstancesOutput{
+		Reservations: []*ec2.Reservation{{
+			Instances: []*ec2.Instance{{
+				InstanceId:       aws.String("i-1234"),
+				PublicIpAddress:  aws.String("203.0.113.10"),
+				PrivateIpAddress: aws.String("10.0.0.10"),
+				Tags: []*ec2.Tag{
+					{Key: aws.String("Name"), Value: aws.String("web-1")},
+				},
+			}},
+		}},
+	}, true)
+	return nil
+}
+
+func (m *mockEC2Client) DescribeAddresses(
+	params *ec2.DescribeAddressesInput,
+) (*ec2.DescribeAddressesOutput, error) {
+	return &ec2.DescribeAddressesOutput{
+		Addresses: []*ec2.Address{
+			{
+				PublicIp:     aws.String("203.0.113.10"),
+				AllocationId: aws.String("eipalloc-1"),
+				InstanceId:   aws.String("i-1234"),
+			},
+			{
+				PublicIp:     aws.String("203.0.113.11"),
+				AllocationId: aws.String("eipalloc-2"),
+			},
+		},
+	}, nil
+}
+
+type mockELBClient struct {
+	elbiface.ELBAPI
+}
+
+func (m *mockELBClient) DescribeLoadBalancersPages(
+	params *elb.DescribeLoadBalancersInput,
+	fn func(*elb.DescribeLoadBalancersOutput, bool) bool,
+) error {
+	fn(&elb.DescribeLoadBalancersOutput{
+		LoadBalancerDescriptions: []*elb.LoadBalancerDescription{{
+			LoadBalancerName: aws.String("classic"),
+			DNSName:          aws.String("classic-1.eu-west-1.elb.amazonaws.com"),
+		}},
+	}, true)
+	return nil
+}
+
+type mockELBv2Client struct {
+	elbv2iface.ELBV2API
+}
+
+func (m *mockELBv2Client) DescribeLoadBalancersPages(
+	params *elbv2.DescribeLoadBalancersInput,
+	fn func(*elbv2.DescribeLoadBalancersOutput, bool) bool,
+) error {
+	fn(&elbv2.DescribeLoadBalancersOutput{
+		LoadBalancers: []*elbv2.LoadBalancer{{
+			LoadBalancerName: aws.String("api"),
+			Type:             aws.String("application"),
+			DNSName:          aws.String("api-1.eu-west-1.elb.amazonaws.com"),
+		}},
+	}, true)
+	return nil
+}
+
+type mockRDSClient struct {
+	rdsiface.RDSAPI
+}
+
+func (m *mockRDSClient) DescribeDBInstancesPages(
+	params *rds.DescribeDBInstancesInput,
+	fn func(*rds.DescribeDBInstancesOutput, bool) bool,
+) error {
+	fn(&rds.DescribeDBInstancesOutput{
+		DBInstances: []*rds.DBInstance{{
+			DBInstanceIdentifier: aws.String("db"),
+			Endpoint: &rds.Endpoint{
+				Address: aws.String("db.abc.eu-west-1.rds.amazonaws.com"),
+			},
+		}},
+	}, true)
+	return nil
+}
+
+func (m *mockRDSClient) DescribeDBClustersPages(
+	params *rds.DescribeDBClustersInput,
+	fn func(*rds.DescribeDBClustersOutput, bool) bool,
+) error {
+	fn(&rds.DescribeDBClustersOutput{}, true)
+	return nil
+}
+
+type mockCloudFrontClient struct {
+	cloudfrontiface.CloudFrontAPI
+}
+
+func (m *mockCloudFrontClient) ListDistributionsPages(
+	params *cloudfront.ListDistributionsInput,
+	fn func(*cloudfront.ListDistributionsOutput, bool) bool,
+) error {
+	fn(&cloudfront.ListDistributionsOutput{
+		DistributionList: &cloudfront.DistributionList{
+			Items: []*cloudfront.DistributionSummary{{
+				Id:         aws.String("E1234"),
+				DomainName: aws.String("d111111abcdef8.cloudfront.net"),
+			}},
+		},
+	}, true)
+	return nil
+}
+
+func newInventory() *Inventory {
+	inventory := NewInventory()
+	inventory.AddEC2(&mockEC2Client{})
+	inventory.AddELB(&mockELBClient{})
+	inventory.AddELBv2(&mockELBv2Client{})
+	inventory.AddRDS(&mockRDSClient{})
+	inventory.AddCloudFront(&mockCloudFrontClient{})
+	return inventory
+}
+
+var resourcetests = []struct {
+	record    *route53.ResourceRecordSet
+	resources []string
+	owned     bool
+}{
+	{newRecord("web.example.com.", "A", "203.0.113.10"), []string{"EC2 instance i-1234 (web-1)"}, true},
+	{newRecord("internal.example.com.", "A", "10.0.0.10"), []string{"EC2 instance i-1234 (web-1)"}, true},
+	{newRecord("spare.example.com.", "A", "203.0.113.11"), []string{"Elastic IP eipalloc-2"}, true},
+	{newRecord("old.example.com.", "A", "198.51.100.1"), nil, false},
+	{newRecord("onprem.example.com.", "A", "192.168.0.1"), nil, true},
+	{
+		newAlias("www.example.com.", "A", "dualstack.classic-1.eu-west-1.elb.amazonaws.com."),
+		[]string{"ELB classic"},
+		true,
+	},
+	{
+		newRecord("api.example.com.", "CNAME", "API-1.eu-west-1.elb.amazonaws.com"),
+		[]string{"APPLICATION LB api"},
+		true,
+	},
+	{
+		newRecord("db.example.com.", "CNAME", "db.abc.eu-west-1.rds.amazonaws.com"),
+		[]string{"RDS instance db"},
+		true,
+	},
+	{
+		newAlias("cdn.example.com.", "A", "d111111abcdef8.cloudfront.net."),
+		[]string{"CloudFront E1234"},
+		true,
+	},
+}
+
+func TestMapResources(t *testing.T) {
+	inventory := newInventory()
+	for _, tt := range resourcetests {
+		rtl := NewReferenceTreeList([]*route53.ResourceRecordSet{tt.record})
+		rtl.MapResources(inventory)
+		resources := rtl.Roots()[0].Resources()
+		if strings.Join(resources, ";") != strings.Join(tt.resources, ";") {
+			t.Errorf(
+				"%s: expected resources %v, got %v",
+				*tt.record.Name,
+				tt.resources,
+				resources,
+			)
+		}
+		warned := false
+		for _, warning := range rtl.Warnings() {
+			warned = warned || warning.Kind == NotOwned
+		}
+		if warned == tt.owned {
+			t.Errorf("%s: unexpected warnings %v", *tt.record.Name, rtl.Warnings())
+		}
+	}
+}
+
+func TestMapResourcesString(t *testing.T) {
+	rtl := NewReferenceTreeList([]*route53.ResourceRecordSet{
+		newRecord("web.example.com.", "A", "203.0.113.10"),
+		newRecord("www.example.com.", "CNAME", "web.example.com."),
+	})
+	rtl.MapResources(newInventory())
+	expected := `web.example.com. A 203.0.113.10 [EC2 instance i-1234 (web-1)]
+	www.example.com. CNAME web.example.com.
+`
+	if output := rtl.String(); output != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, output)
+	}
+}
@@ -0,0 +1,178 @@
+package roosa
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Chain is a record with the paths of names it resolves through, up to
+// the values of the roots of its reference trees, or the names out of the
+// domain they point to.
+type Chain struct {
+	Name  string   `json:"name"`
+	Type  string   `json:"type"`
+	Paths []string `json:"paths"`
+}
+
+// key identifies the record of c.
+func (c *Chain) key() string {
+	return fmt.Sprintf("%s %s", lookupKey(c.Name), c.Type)
+}
+
+// data describes the paths of c.
+func (c *Chain) data() string {
+	return strings.Join(c.Paths, "; ")
+}
+
+// Snapshot holds the dependency chains of all the records in reference
+// trees, to be compared with other snapshots.
+type Snapshot struct {
+	Chains []*Chain `json:"chains"`
+}
+
+// Snapshot returns the dependency chains of the reference trees records.
+func (rtl *ReferenceTreeList) Snapshot() *Snapshot {
+	paths := map[*Node][]string{}
+	var walk func(node *Node, path []string)
+	walk = func(node *Node, path []string) {
+		paths[node] = append(paths[node], strings.Join(path, " -> "))
+		path = append([]string{node.Name()}, path...)
+		for _, child := range node.Children() {
+			walk(child, path)
+		}
+	}
+	for _, root := range rtl.Roots() {
+		end := []string{}
+		for _, reference := range root.External() {
+			end = append(end, reference.Target)
+		}
+		if len(root.References()) == 0 {
+			end = root.Values()
+		}
+		walk(root, []string{strings.Join(end, ", ")})
+	}
+	// Records with the same name and type, using routing policies, share
+	// their chain
+	chains := map[string]*Chain{}
+	snapshot := &Snapshot{Chains: []*Chain{}}
+	for _, node := range rtl.treeNodes() {
+		chain := &Chain{Name: node.Name(), Type: node.Type()}
+		if existing, ok := chains[chain.key()]; ok {
+			chain = existing
+		} else {
+			chains[chain.key()] = chain
+			snapshot.Chains = append(snapshot.Chains, chain)
+		}
+		chain.Paths = append(chain.Paths, paths[node]...)
+		sort.Strings(chain.Paths)
+	}
+	sort.SliceStable(snapshot.Chains, func(i, j int) bool {
+		return snapshot.Chains[i].key() < snapshot.Chains[j].key()
+	})
+	return snapshot
+}
+
+// WriteSnapshot writes snapshot in JSON format.
+func WriteSnapshot(w io.Writer, snapshot *Snapshot) error {
+	out, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", out)
+	return err
+}
+
+// ReadSnapshot returns the Snapshot in JSON format in r.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	if err := json.NewDecoder(r).Decode(snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// ChainChange holds both versions of the chain of a record re-pointed in
+// between two snapshots.
+type ChainChange struct {
+	From *Chain
+	To   *Chain
+}
+
+// SnapshotDiff holds the differences between two snapshots.
+type SnapshotDiff struct {
+	Added   []*Chain
+	Removed []*Chain
+	Changed []ChainChange
+}
+
+// Empty returns true if there are no differences.
+func (d *SnapshotDiff) Empty() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Changed) == 0
+}
+
+// String lists differences one per line, prefixing chains appeared with
+// +, disappeared with -, and re-pointed with ~.
+func (d *SnapshotDiff) String() (output string) {
+	lines := []string{}
+	for _, chain := range d.Removed {
+		lines = append(
+			lines,
+			fmt.Sprintf("- %s %s: %s", chain.Name, chain.Type, chain.data()),
+		)
+	}
+	for _, chain := range d.Added {
+		lines = append(
+			lines,
+			fmt.Sprintf("+ %s %s: %s", chain.Name, chain.Type, chain.data()),
+		)
+	}
+	for _, change := range d.Changed {
+		lines = append(
+			lines,
+			fmt.Sprintf(
+				"~ %s %s: %s => %s",
+				change.To.Name,
+				change.To.Type,
+				change.From.data(),
+				change.To.data(),
+			),
+		)
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i][2:] < lines[j][2:]
+	})
+	for _, line := range lines {
+		output += line + "\n"
+	}
+	return
+}
+
+// DiffSnapshots compares the chains in snapshot from with the ones in
+// snapshot to.
+func DiffSnapshots(from, to *Snapshot) *SnapshotDiff {
+	diff := &SnapshotDiff{}
+	remaining := map[string]*Chain{}
+	for _, chain := range from.Chains {
+		remaining[chain.key()] = chain
+	}
+	for _, chain := range to.Chains {
+		old, ok := remaining[chain.key()]
+		if !ok {
+			diff.Added = append(diff.Added, chain)
+			continue
+		}
+		delete(remaining, chain.key())
+		if old.data() != chain.data() {
+			diff.Changed = append(diff.Changed, ChainChange{From: old, To: chain})
+		}
+	}
+	for _, chain := range from.Chains {
+		if _, ok := remaining[chain.key()]; ok {
+			diff.Removed = append(diff.Removed, chain)
+		}
+	}
+	return diff
+}
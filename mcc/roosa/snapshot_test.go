@@ -0,0 +1,70 @@
+package roosa
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+var snapshotDiff = `+ new.example.com. CNAME: root.example.com. -> 127.0.0.1
+~ root-grandson.example.com. CNAME: root-son.example.com. -> root.example.com. -> 127.0.0.1 => root-son.example.com. -> root2.example.com. -> 127.0.0.2
+- root-son-sibling.example.com. CNAME: root.example.com. -> 127.0.0.1
+~ root-son.example.com. CNAME: root.example.com. -> 127.0.0.1 => root2.example.com. -> 127.0.0.2
+~ service1.example.com. CNAME: root.example.com. -> 127.0.0.1; root2.example.com. -> 127.0.0.2 => root.example.com. -> 127.0.0.1
+`
+
+func TestSnapshot(t *testing.T) {
+	snapshot := NewReferenceTreeList(generateRoute53RRS()).Snapshot()
+	chains := map[string]string{}
+	for _, chain := range snapshot.Chains {
+		chains[chain.Name] = chain.data()
+	}
+	for name, data := range map[string]string{
+		"root.example.com.":          "127.0.0.1",
+		"multiple-a.example.com.":    "127.0.0.1, 127.0.0.2, 127.0.0.3",
+		"root-grandson.example.com.": "root-son.example.com. -> root.example.com. -> 127.0.0.1",
+		"service1.example.com.": "root.example.com. -> 127.0.0.1; " +
+			"root2.example.com. -> 127.0.0.2",
+		"test.example.com.": "test.example2.com",
+	} {
+		if chains[name] != data {
+			t.Errorf("%s: expected '%s', got '%s'", name, data, chains[name])
+		}
+	}
+	buffer := &bytes.Buffer{}
+	if err := WriteSnapshot(buffer, snapshot); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadSnapshot(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := DiffSnapshots(snapshot, read); !diff.Empty() {
+		t.Errorf("Snapshot should be read as written:\n%s", diff)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	from := NewReferenceTreeList(generateRoute53RRS()).Snapshot()
+	records := []*route53.ResourceRecordSet{
+		newRecord("new.example.com.", "CNAME", "root.example.com"),
+	}
+	for _, record := range generateRoute53RRS() {
+		switch {
+		case *record.Name == "root-son-sibling.example.com.":
+			continue
+		case *record.Name == "root-son.example.com.":
+			record = newRecord(*record.Name, "CNAME", "root2.example.com")
+		case *record.Name == "service1.example.com." &&
+			*record.ResourceRecords[0].Value == "root2.example.com":
+			continue
+		}
+		records = append(records, record)
+	}
+	to := NewReferenceTreeList(records).Snapshot()
+	diff := DiffSnapshots(from, to)
+	if diff.String() != snapshotDiff {
+		t.Errorf("Expected:\n%s\nGot:\n%s", snapshotDiff, diff)
+	}
+}
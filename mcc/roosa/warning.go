@@ -0,0 +1,68 @@
+package roosa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Warning kinds.
+const (
+	// Cycle is a chain of records referencing each other.
+	Cycle = "cycle"
+	// Depth is a chain of references longer than allowed.
+	Depth = "depth"
+	// MultipleParents is a name with records pointing to several
+	// different records.
+	MultipleParents = "parents"
+	// NotOwned is a record pointing to a public address out of the
+	// inventory.
+	NotOwned = "not-owned"
+	// PTRMismatch is a PTR record pointing to a name which doesn't
+	// resolve to the address it's named after.
+	PTRMismatch = "ptr"
+)
+
+// Warning is a problem found building the reference trees. Chain holds
+// the names involved, in reference order for cycles and depth warnings,
+// the name followed by its targets for multiple parents, the name
+// followed by the address for addresses not owned, and the name followed
+// by its target for PTR mismatches.
+type Warning struct {
+	Kind  string
+	Chain []string
+}
+
+// String returns a line describing w.
+func (w *Warning) String() string {
+	switch w.Kind {
+	case MultipleParents:
+		return fmt.Sprintf(
+			"%s: %s has multiple candidate parents: %s",
+			w.Kind,
+			w.Chain[0],
+			strings.Join(w.Chain[1:], ", "),
+		)
+	case NotOwned:
+		return fmt.Sprintf(
+			"%s: %s points to %s, which is not owned",
+			w.Kind,
+			w.Chain[0],
+			w.Chain[1],
+		)
+	case PTRMismatch:
+		return fmt.Sprintf(
+			"%s: %s points to %s, which doesn't resolve back to it",
+			w.Kind,
+			w.Chain[0],
+			w.Chain[1],
+		)
+	case Depth:
+		return fmt.Sprintf(
+			"%s: %d references: %s",
+			w.Kind,
+			len(w.Chain)-1,
+			strings.Join(w.Chain, " -> "),
+		)
+	}
+	return fmt.Sprintf("%s: %s", w.Kind, strings.Join(w.Chain, " -> "))
+}
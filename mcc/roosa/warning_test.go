@@ -0,0 +1,107 @@
+package roosa
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+func weighted(
+	record *route53.ResourceRecordSet,
+	id string,
+) *route53.ResourceRecordSet {
+	record.SetIdentifier = aws.String(id)
+	return record
+}
+
+var warningtests = []struct {
+	records  []*route53.ResourceRecordSet
+	maxDepth int
+	output   string
+	warnings []string
+}{
+	{
+		records: generateRoute53RRS(),
+		output:  sortedOutput,
+		warnings: []string{
+			"parents: service1.example.com. has multiple candidate parents: " +
+				"root.example.com, root2.example.com",
+		},
+	},
+	{
+		records: []*route53.ResourceRecordSet{
+			newRecord("a.example.com.", "CNAME", "b.example.com."),
+			newRecord("b.example.com.", "CNAME", "a.example.com."),
+			newRecord("c.example.com.", "CNAME", "a.example.com."),
+			newRecord("e.example.com.", "CNAME", "c.example.com."),
+			newRecord("d.example.com.", "A", "10.0.0.1"),
+		},
+		// Records referencing a cycle are roots pointing out of the trees
+		output: `c.example.com. CNAME a.example.com.
+	e.example.com. CNAME c.example.com.
+d.example.com. A 10.0.0.1
+`,
+		warnings: []string{
+			"cycle: a.example.com. -> b.example.com. -> a.example.com.",
+		},
+	},
+	{
+		// A cycle reachable from a root
+		records: []*route53.ResourceRecordSet{
+			weighted(newRecord("b.example.com.", "A", "10.0.0.1"), "one"),
+			weighted(newRecord("b.example.com.", "CNAME", "a.example.com."), "two"),
+			newRecord("a.example.com.", "CNAME", "c.example.com."),
+			newRecord("c.example.com.", "CNAME", "b.example.com."),
+		},
+		output: `b.example.com. A 10.0.0.1
+	c.example.com. CNAME b.example.com.
+		a.example.com. CNAME c.example.com.
+`,
+		warnings: []string{
+			"cycle: a.example.com. -> c.example.com. -> b.example.com. -> a.example.com.",
+		},
+	},
+	{
+		records: []*route53.ResourceRecordSet{
+			newRecord("a.example.com.", "A", "10.0.0.1"),
+			newRecord("b.example.com.", "CNAME", "a.example.com."),
+			newRecord("c.example.com.", "CNAME", "b.example.com."),
+			newRecord("d.example.com.", "CNAME", "c.example.com."),
+			newRecord("e.example.com.", "CNAME", "b.example.com."),
+		},
+		maxDepth: 2,
+		output: `a.example.com. A 10.0.0.1
+	b.example.com. CNAME a.example.com.
+		c.example.com. CNAME b.example.com.
+			d.example.com. CNAME c.example.com.
+		e.example.com. CNAME b.example.com.
+`,
+		warnings: []string{
+			"depth: 3 references: d.example.com. -> c.example.com. -> " +
+				"b.example.com. -> a.example.com.",
+		},
+	},
+}
+
+func TestWarnings(t *testing.T) {
+	for _, tt := range warningtests {
+		rtl := NewReferenceTreeList(tt.records)
+		if tt.maxDepth > 0 {
+			rtl.MaxDepth = tt.maxDepth
+		}
+		if output := rtl.String(); output != tt.output {
+			t.Errorf("Expected:\n%s\nGot:\n%s", tt.output, output)
+		}
+		warnings := rtl.Warnings()
+		if len(warnings) != len(tt.warnings) {
+			t.Errorf("Expected warnings %v, got %v", tt.warnings, warnings)
+			continue
+		}
+		for i, warning := range warnings {
+			if warning.String() != tt.warnings[i] {
+				t.Errorf("Expected '%s', got '%s'", tt.warnings[i], warning)
+			}
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"log"
 
 	"github.com/spf13/cobra"
@@ -17,15 +16,11 @@ var addCmd = &cobra.Command{
 	Short: "Adds a redirect",
 	Long:  ``,
 	Run: func(cmd *cobra.Command, args []string) {
-		redirects, err := trek.ReadFromPipe()
+		output, err := trek.Add(readRedirects(), original, final, renderer())
 		if err != nil {
 			log.Fatal(err.Error())
 		}
-		output, err := trek.Add(redirects, original, final)
-		if err != nil {
-			log.Fatal(err.Error())
-		}
-		fmt.Printf("%s", output)
+		writeRedirects(output)
 	},
 }
 
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/trek/trek"
+)
+
+var hosts []string
+var flatten bool
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Checks redirects for chains and loops",
+	Long: `check follows every redirect through the rest of them, and lists
+loops, chains of more than one hop, and redirects to URLs which are
+redirected themselves. It exits with status 1 when any are left.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		content := readRedirects()
+		if flatten {
+			rendered, flattened, issues, err := trek.Flatten(
+				content,
+				hosts,
+				renderer(),
+			)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			for _, issue := range flattened {
+				log.Printf("Flattened %s", issue)
+			}
+			writeRedirects(rendered)
+			for _, issue := range issues {
+				log.Println(issue)
+			}
+			if len(issues) > 0 {
+				os.Exit(1)
+			}
+			return
+		}
+		list, err := trek.Parse(content)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		issues := list.Check(hosts)
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+		if len(issues) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(checkCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	checkCmd.PersistentFlags().StringSliceVarP(
+		&hosts,
+		"hosts",
+		"",
+		[]string{},
+		"Hostnames of the servers using the redirects map",
+	)
+	checkCmd.PersistentFlags().BoolVarP(
+		&flatten,
+		"flatten",
+		"",
+		false,
+		"Rewrite chains to their final destination",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// checkCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
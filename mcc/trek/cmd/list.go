@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/trek/trek"
+)
+
+var format string
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists redirects",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		list, err := trek.Parse(readRedirects())
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, duplicate := range list.Duplicates() {
+			log.Printf(
+				"Duplicate redirect from %s to %s",
+				duplicate.Original,
+				duplicate.Final,
+			)
+		}
+		switch format {
+		case "table":
+			fmt.Printf("%s", list.Table())
+		case "json":
+			output, err := list.JSON()
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			fmt.Printf("%s", output)
+		default:
+			log.Fatalf("Unknown format %s", format)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(listCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	listCmd.PersistentFlags().StringVarP(
+		&format,
+		"format",
+		"",
+		"table",
+		"Output format: table or json",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// listCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/trek/trek"
+)
+
+// removeCmd represents the remove command
+var removeCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Removes a redirect",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, err := trek.Remove(readRedirects(), original, renderer())
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		writeRedirects(output)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(removeCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	removeCmd.PersistentFlags().StringVarP(
+		&original,
+		"original",
+		"",
+		"",
+		"Path or URI to stop redirecting",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// removeCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/trek/trek"
+)
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Renders redirects for another server",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		rendered, err := trek.Render(readRedirects(), renderer())
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		fmt.Printf("%s", rendered)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(renderCmd)
+}
@@ -2,19 +2,75 @@ package cmd
 
 import (
 	"fmt"
+	"io/ioutil"
+	"log"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/trek/trek"
 )
 
 var cfgFile string
+var file string
+var outputFormat string
+var haproxyMap string
 
 // RootCmd represents the base command when called without any subcommands
 var RootCmd = &cobra.Command{
 	Use:   "trek",
 	Short: "Redirect configuration",
 	Long: `trek helps to configure redirects in Nginx redirects.map.
-Currently this tool only allows to add a line to the stream on standard input.`,
+Redirects are read from standard input and written to standard output,
+unless a file is given with --file, which is then edited in place.
+Redirects can be rendered for Apache, HAProxy or S3 websites too.`,
+}
+
+// renderer returns the renderer for the output format.
+func renderer() trek.Renderer {
+	if outputFormat == "haproxy" {
+		return trek.HAProxy{MapFile: haproxyMap}
+	}
+	renderer, ok := trek.Renderers[outputFormat]
+	if !ok {
+		log.Fatalf("Unknown output %s", outputFormat)
+	}
+	return renderer
+}
+
+// readRedirects returns the redirects in the file, or on standard input.
+func readRedirects() string {
+	if file == "" {
+		redirects, err := trek.ReadFromPipe()
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		return redirects
+	}
+	redirects, err := ioutil.ReadFile(file)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	return string(redirects)
+}
+
+// writeRedirects replaces the redirects in the file, keeping a backup, or
+// writes them to standard output. Only nginx redirects can be read back,
+// so they're the only ones written to the file.
+func writeRedirects(redirects string) {
+	if file != "" && outputFormat != "nginx" {
+		log.Fatal("Only nginx redirects can be written to --file")
+	}
+	if file == "" {
+		fmt.Printf("%s", redirects)
+		return
+	}
+	backup, err := trek.WriteFile(file, redirects)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	log.Printf("Previous redirects saved to %s", backup)
 }
 
 // Execute adds all child commands to the root command sets flags appropriately.
@@ -25,3 +81,30 @@ func Execute() {
 		os.Exit(-1)
 	}
 }
+
+func init() {
+	RootCmd.PersistentFlags().StringVarP(
+		&file,
+		"file",
+		"",
+		"",
+		"Redirects file to edit in place, instead of standard input",
+	)
+	RootCmd.PersistentFlags().StringVarP(
+		&outputFormat,
+		"output",
+		"",
+		"nginx",
+		fmt.Sprintf(
+			"Output format: %s",
+			strings.Join(trek.RendererNames(), ", "),
+		),
+	)
+	RootCmd.PersistentFlags().StringVarP(
+		&haproxyMap,
+		"haproxy-map",
+		"",
+		"/etc/haproxy/redirects.map",
+		"Path to the HAProxy map file, for haproxy output",
+	)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/poka-yoke/spaceflight/mcc/trek/trek"
+)
+
+// updateCmd represents the update command
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Updates a redirect",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, err := trek.Update(readRedirects(), original, final, renderer())
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		writeRedirects(output)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(updateCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	updateCmd.PersistentFlags().StringVarP(
+		&original,
+		"original",
+		"",
+		"",
+		"Path or URI to redirect",
+	)
+	updateCmd.PersistentFlags().StringVarP(
+		&final,
+		"final",
+		"",
+		"",
+		"Path or URI to redirect to",
+	)
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// updateCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+}
@@ -0,0 +1,149 @@
+package trek
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Issue kinds.
+const (
+	// Loop is a redirect ending up back at a source it went through.
+	Loop = "loop"
+	// Chain is a redirect to a path which is redirected itself, taking
+	// more than one hop to its final destination.
+	Chain = "chain"
+	// Source is a redirect to a URL which is redirected itself.
+	Source = "source"
+)
+
+// Issue is a redirect which doesn't lead to its final destination in one
+// hop, with the destinations it goes through, starting with its original.
+type Issue struct {
+	Kind     string
+	Redirect *Redirect
+	Chain    []string
+}
+
+// Destination returns the final destination of the redirect of i.
+func (i *Issue) Destination() string {
+	return i.Chain[len(i.Chain)-1]
+}
+
+// String returns the kind and the chain of i.
+func (i *Issue) String() string {
+	return fmt.Sprintf("%s: %s", i.Kind, strings.Join(i.Chain, " -> "))
+}
+
+// captures matches the references to captures in map values.
+var captures = regexp.MustCompile(`\$(\d)`)
+
+// follow returns the redirect from target, which is a path, or a URL of
+// a server redirect or of one of hosts, the servers using the map
+// entries, and the destination it redirects target to.
+func (l *RedirectList) follow(target string, hosts []string) (
+	*Redirect, string,
+) {
+	path := target
+	if IsURL(target) {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, ""
+		}
+		host := u.Hostname()
+		for _, redirect := range l.Redirects {
+			if redirect.Server && redirect.Original == host {
+				return redirect, redirect.Final
+			}
+		}
+		served := false
+		for _, name := range hosts {
+			served = served || name == host
+		}
+		if !served {
+			return nil, ""
+		}
+		path = u.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+	}
+	// As nginx does, exact matches take precedence over regular
+	// expressions, which are checked in order
+	for _, redirect := range l.Redirects {
+		if !redirect.Server && redirect.Original == path {
+			return redirect, redirect.Final
+		}
+	}
+	for _, redirect := range l.Redirects {
+		expression, insensitive, regex := pattern(redirect.Original)
+		if redirect.Server || !regex {
+			continue
+		}
+		if insensitive {
+			expression = "(?i)" + expression
+		}
+		re, err := regexp.Compile(expression)
+		if err != nil {
+			continue
+		}
+		match := re.FindStringSubmatchIndex(path)
+		if match == nil {
+			continue
+		}
+		// The value replaces the whole path, not only the part matched
+		final := captures.ReplaceAllString(redirect.Final, "${$1}")
+		return redirect, string(re.ExpandString(nil, final, path, match))
+	}
+	return nil, ""
+}
+
+// Check follows every redirect through the rest of l, and returns the
+// issues of those not leading to their final destination in one hop.
+// Hosts are the names of the servers using the map entries, so URLs on
+// them are followed too.
+func (l *RedirectList) Check(hosts []string) (issues []*Issue) {
+	for _, redirect := range l.Redirects {
+		issue := &Issue{
+			Redirect: redirect,
+			Chain:    []string{redirect.Original, redirect.Final},
+		}
+		visited := map[*Redirect]bool{redirect: true}
+		for {
+			next, destination := l.follow(issue.Destination(), hosts)
+			if next == nil {
+				break
+			}
+			if issue.Kind == "" {
+				issue.Kind = Chain
+				if IsURL(issue.Destination()) {
+					issue.Kind = Source
+				}
+			}
+			if visited[next] {
+				issue.Kind = Loop
+				break
+			}
+			visited[next] = true
+			issue.Chain = append(issue.Chain, destination)
+		}
+		if issue.Kind != "" {
+			issues = append(issues, issue)
+		}
+	}
+	return
+}
+
+// Flatten points the redirects in chains straight to their final
+// destination, and returns the issues fixed. Loops are left as they are.
+func (l *RedirectList) Flatten(hosts []string) (flattened []*Issue) {
+	for _, issue := range l.Check(hosts) {
+		if issue.Kind == Loop {
+			continue
+		}
+		issue.Redirect.Final = issue.Destination()
+		flattened = append(flattened, issue)
+	}
+	return
+}
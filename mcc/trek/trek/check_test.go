@@ -0,0 +1,91 @@
+package trek
+
+import (
+	"testing"
+)
+
+var checktests = []struct {
+	content string
+	hosts   []string
+	issues  []string
+}{
+	{"/a /b;\n/c /d;\n", nil, nil},
+	{
+		"/a /b;\n/b /c;\n/c /d;\n",
+		nil,
+		[]string{"chain: /a -> /b -> /c -> /d", "chain: /b -> /c -> /d"},
+	},
+	{
+		"/a /b;\n/b /a;\n/c /c;\n",
+		nil,
+		[]string{"loop: /a -> /b -> /a", "loop: /b -> /a -> /b", "loop: /c -> /c"},
+	},
+	{
+		"/a http://www.example.com/b;\n/b /c;\n",
+		[]string{"www.example.com"},
+		[]string{"source: /a -> http://www.example.com/b -> /c"},
+	},
+	{"/a http://www.example.com/b;\n/b /c;\n", nil, nil},
+	{
+		"/help http://help.example.com/;\n" +
+			"server {\n" +
+			"\tserver_name help.example.com;\n" +
+			"\treturn 301 http://www.example.com/support;\n" +
+			"}\n",
+		nil,
+		[]string{"source: /help -> http://help.example.com/ -> http://www.example.com/support"},
+	},
+	{
+		"/a /old/page;\n~*^/OLD/(.*)$ /new/$1;\n",
+		nil,
+		[]string{"chain: /a -> /old/page -> /new/page"},
+	},
+	{
+		"/a /old/x;\n~^/old/ /news;\n",
+		nil,
+		[]string{"chain: /a -> /old/x -> /news"},
+	},
+	{
+		"/a /blog/2017/post;\n~/(\\d+)/ /archive/$1;\n",
+		nil,
+		[]string{"chain: /a -> /blog/2017/post -> /archive/2017"},
+	},
+}
+
+func TestCheck(t *testing.T) {
+	for _, tt := range checktests {
+		list, err := Parse(tt.content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		issues := list.Check(tt.hosts)
+		if len(issues) != len(tt.issues) {
+			t.Errorf("Expected %v, got %v", tt.issues, issues)
+			continue
+		}
+		for i, issue := range issues {
+			if issue.String() != tt.issues[i] {
+				t.Errorf("Expected %s, got %s", tt.issues[i], issue)
+			}
+		}
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	list, err := Parse("/a /b;\n/b /c;\n/c /d;\n/e /f;\n/f /e;\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flattened := list.Flatten(nil); len(flattened) != 2 {
+		t.Errorf("Expected 2 chains flattened, got %v", flattened)
+	}
+	expected := "/a /d;\n/b /d;\n/c /d;\n/e /f;\n/f /e;\n"
+	if output := list.String(); output != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, output)
+	}
+	for _, issue := range list.Check(nil) {
+		if issue.Kind != Loop {
+			t.Errorf("Only loops should be left, got %s", issue)
+		}
+	}
+}
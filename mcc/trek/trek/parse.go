@@ -0,0 +1,328 @@
+package trek
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// token is a word, or one of ";", "{" and "}", or a comment, in nginx
+// configuration, and the line it's found in. Inline comments follow other
+// tokens in their line.
+type token struct {
+	value   string
+	quoted  bool
+	comment bool
+	inline  bool
+	line    int
+}
+
+// special returns true if t is s, and not a quoted string or a comment.
+func (t token) special(s string) bool {
+	return !t.quoted && !t.comment && t.value == s
+}
+
+// tokenize splits nginx configuration in tokens. Comments start with # in
+// between tokens, and their value is the rest of the line after it.
+func tokenize(content string) (tokens []token, err error) {
+	line := 1
+	inline := false
+	runes := []rune(content)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			inline = false
+			continue
+		case unicode.IsSpace(r):
+			continue
+		case r == '#':
+			value := ""
+			for i < len(runes)-1 && runes[i+1] != '\n' {
+				i++
+				value += string(runes[i])
+			}
+			tokens = append(tokens, token{
+				value:   value,
+				comment: true,
+				inline:  inline,
+				line:    line,
+			})
+		case r == ';' || r == '{' || r == '}':
+			tokens = append(tokens, token{value: string(r), line: line})
+		case r == '"' || r == '\'':
+			value := ""
+			start := line
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				// As nginx does, only quotes and backslashes are escaped,
+				// so regular expressions keep their backslashes
+				if runes[i] == '\\' && i < len(runes)-1 &&
+					strings.ContainsRune("\"'\\", runes[i+1]) {
+					i++
+				}
+				if runes[i] == '\n' {
+					line++
+				}
+				value += string(runes[i])
+			}
+			if i >= len(runes) {
+				err = fmt.Errorf("Unterminated string in line %d", start)
+				return
+			}
+			tokens = append(tokens, token{value: value, quoted: true, line: start})
+		default:
+			value := ""
+			for ; i < len(runes); i++ {
+				// Braces of variables, as in ${name}, don't delimit words
+				if runes[i] == '{' && strings.HasSuffix(value, "$") {
+					for ; i < len(runes) && runes[i] != '}'; i++ {
+						value += string(runes[i])
+					}
+					if i == len(runes) {
+						break
+					}
+				} else if unicode.IsSpace(runes[i]) ||
+					strings.ContainsRune(";{}", runes[i]) {
+					i--
+					break
+				}
+				value += string(runes[i])
+			}
+			tokens = append(tokens, token{value: value, line: line})
+		}
+		inline = true
+	}
+	return
+}
+
+// directive is an nginx directive, with its block if it has one, the
+// comments before it, and its inline comment. Trailing are the comments at
+// the end of its block.
+type directive struct {
+	name     string
+	args     []string
+	block    []*directive
+	line     int
+	comments []string
+	inline   string
+	trailing []string
+}
+
+// parseDirectives returns the directives in tokens, up to the end of the
+// block they're in, the comments left at its end, and the tokens left.
+func parseDirectives(tokens []token, nested bool) (
+	directives []*directive, trailing []string, rest []token, err error,
+) {
+	var last *directive
+	for len(tokens) > 0 {
+		t := tokens[0]
+		if t.comment {
+			if t.inline && last != nil {
+				last.inline = t.value
+			} else {
+				trailing = append(trailing, t.value)
+			}
+			tokens = tokens[1:]
+			continue
+		}
+		if t.special("}") {
+			if !nested {
+				err = fmt.Errorf("Unexpected } in line %d", t.line)
+			}
+			rest = tokens[1:]
+			return
+		}
+		if t.special(";") || t.special("{") {
+			err = fmt.Errorf("Unexpected %s in line %d", t.value, t.line)
+			return
+		}
+		d := &directive{name: t.value, line: t.line, comments: trailing}
+		trailing = nil
+		tokens = tokens[1:]
+		for len(tokens) > 0 && !tokens[0].special(";") &&
+			!tokens[0].special("{") && !tokens[0].special("}") {
+			if tokens[0].comment {
+				d.comments = append(d.comments, tokens[0].value)
+			} else {
+				d.args = append(d.args, tokens[0].value)
+			}
+			tokens = tokens[1:]
+		}
+		switch {
+		case len(tokens) == 0 || tokens[0].special("}"):
+			err = fmt.Errorf("Missing ; in line %d", d.line)
+			return
+		case tokens[0].special("{"):
+			d.block, d.trailing, tokens, err = parseDirectives(tokens[1:], true)
+			if err != nil {
+				return
+			}
+		default:
+			tokens = tokens[1:]
+		}
+		directives = append(directives, d)
+		last = d
+	}
+	if nested {
+		err = fmt.Errorf("Missing } at end of file")
+	}
+	return
+}
+
+// allComments returns the comments before d, in its block and inline, in
+// order.
+func (d *directive) allComments() (comments []string) {
+	comments = append(comments, d.comments...)
+	for _, nested := range d.block {
+		comments = append(comments, nested.allComments()...)
+	}
+	comments = append(comments, d.trailing...)
+	if d.inline != "" {
+		comments = append(comments, d.inline)
+	}
+	return
+}
+
+// parseServer returns the redirects of a server block, one for each of
+// its names, which must only listen and return a redirect. As in nginx,
+// servers listen on port 80 unless told otherwise. Comments in the block
+// are kept before the first redirect.
+func parseServer(server *directive) (redirects []*Redirect, err error) {
+	names, listen := []string{}, []string{}
+	code, final := 0, ""
+	for _, d := range server.block {
+		switch d.name {
+		case "listen":
+			if len(d.args) == 0 {
+				err = fmt.Errorf("Unsupported listen in line %d", d.line)
+				return
+			}
+			listen = append(listen, strings.Join(d.args, " "))
+		case "server_name":
+			names = append(names, d.args...)
+		case "return":
+			if len(d.args) != 2 {
+				err = fmt.Errorf("Unsupported return in line %d", d.line)
+				return
+			}
+			code, err = strconv.Atoi(d.args[0])
+			if err != nil || code < 300 || code > 399 {
+				err = fmt.Errorf("Unsupported return code in line %d", d.line)
+				return
+			}
+			final = d.args[1]
+		default:
+			err = fmt.Errorf(
+				"Unsupported directive %s in server block in line %d",
+				d.name,
+				d.line,
+			)
+			return
+		}
+	}
+	if len(names) == 0 || final == "" {
+		err = fmt.Errorf("Server block in line %d is not a redirect", server.line)
+		return
+	}
+	if len(listen) == 0 {
+		listen = []string{"80"}
+	}
+	for _, name := range names {
+		redirects = append(redirects, &Redirect{
+			Original: name,
+			Final:    final,
+			Server:   true,
+			Code:     code,
+			Listen:   listen,
+		})
+	}
+	redirects[0].Comments = server.allComments()
+	return
+}
+
+// parseEntry returns the redirect of a map entry.
+func parseEntry(d *directive) (*Redirect, error) {
+	if len(d.args) != 1 || d.block != nil {
+		return nil, fmt.Errorf("Unsupported map entry in line %d", d.line)
+	}
+	return &Redirect{
+		Original: d.name,
+		Final:    d.args[0],
+		Comments: d.comments,
+		Inline:   d.inline,
+	}, nil
+}
+
+// Parse returns the redirects in a map file, either holding its entries
+// or a map block, and the server blocks trek generates for redirects from
+// hostnames. Comments are kept with the redirects they precede, and the
+// rest with the list.
+func Parse(content string) (list *RedirectList, err error) {
+	tokens, err := tokenize(content)
+	if err != nil {
+		return
+	}
+	directives, trailing, _, err := parseDirectives(tokens, false)
+	if err != nil {
+		return
+	}
+	list = &RedirectList{}
+	for _, d := range directives {
+		switch {
+		case d.name == "server" && d.block != nil:
+			var redirects []*Redirect
+			redirects, err = parseServer(d)
+			if err != nil {
+				return nil, err
+			}
+			list.Redirects = append(list.Redirects, redirects...)
+		case d.name == "map" && d.block != nil:
+			if len(list.Map) > 0 {
+				return nil, fmt.Errorf("Only one map block is supported")
+			}
+			list.Map = d.args
+			list.Comments = append(list.Comments, d.comments...)
+			if d.inline != "" {
+				list.Comments = append(list.Comments, d.inline)
+			}
+			list.Trailing = append(list.Trailing, d.trailing...)
+			for _, entry := range d.block {
+				switch entry.name {
+				case "default":
+					if len(entry.args) != 1 {
+						return nil, fmt.Errorf(
+							"Unsupported default in line %d",
+							entry.line,
+						)
+					}
+					list.Default = entry.args[0]
+					list.Comments = append(list.Comments, entry.allComments()...)
+				case "hostnames", "volatile", "include":
+					return nil, fmt.Errorf(
+						"Unsupported map parameter %s in line %d",
+						entry.name,
+						entry.line,
+					)
+				default:
+					var redirect *Redirect
+					redirect, err = parseEntry(entry)
+					if err != nil {
+						return nil, err
+					}
+					list.Redirects = append(list.Redirects, redirect)
+				}
+			}
+		default:
+			var redirect *Redirect
+			redirect, err = parseEntry(d)
+			if err != nil {
+				return nil, err
+			}
+			list.Redirects = append(list.Redirects, redirect)
+		}
+	}
+	list.Trailing = append(list.Trailing, trailing...)
+	return
+}
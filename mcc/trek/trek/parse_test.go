@@ -0,0 +1,202 @@
+package trek
+
+import (
+	"reflect"
+	"testing"
+)
+
+var parsetests = []struct {
+	content   string
+	redirects []Redirect
+	canonical string
+}{
+	{
+		"/en /;\n# Old about page\n/en/about   /about; # moved\n",
+		[]Redirect{
+			{Original: "/en", Final: "/"},
+			{
+				Original: "/en/about",
+				Final:    "/about",
+				Comments: []string{" Old about page"},
+				Inline:   " moved",
+			},
+		},
+		"/en /;\n# Old about page\n/en/about /about; # moved\n",
+	},
+	{
+		"# Redirects\n" +
+			"map $uri $redirect {\n" +
+			"\tdefault /;\n" +
+			"\t# Archive\n" +
+			"\t\"~^/a/(\\d{4})$\" /y/$1; # by year\n" +
+			"\t'~^/x\\.html$' /x;\n" +
+			"\t\"/say \\\"hi\\\"\" /hi;\n" +
+			"\t# End\n" +
+			"}\n" +
+			"# Servers\n" +
+			"server {\n" +
+			"\tlisten 80; # http\n" +
+			"\tserver_name\told.example.com;\n" +
+			"\treturn 301\thttps://www.example.com/;\n" +
+			"}\n",
+		[]Redirect{
+			{
+				Original: "~^/a/(\\d{4})$",
+				Final:    "/y/$1",
+				Comments: []string{" Archive"},
+				Inline:   " by year",
+			},
+			{Original: "~^/x\\.html$", Final: "/x"},
+			{Original: "/say \"hi\"", Final: "/hi"},
+			{
+				Original: "old.example.com",
+				Final:    "https://www.example.com/",
+				Server:   true,
+				Code:     301,
+				Listen:   []string{"80"},
+				Comments: []string{" Servers", " http"},
+			},
+		},
+		"# Redirects\n" +
+			"map $uri $redirect {\n" +
+			"\tdefault /;\n" +
+			"\t# Archive\n" +
+			"\t\"~^/a/(\\\\d{4})$\" /y/$1; # by year\n" +
+			"\t~^/x\\.html$ /x;\n" +
+			"\t\"/say \\\"hi\\\"\" /hi;\n" +
+			"\t# End\n" +
+			"}\n" +
+			"\n" +
+			"# Servers\n" +
+			"# http\n" +
+			"server {\n" +
+			"\tlisten 80;\n" +
+			"\tserver_name\told.example.com;\n" +
+			"\treturn 301\thttps://www.example.com/;\n" +
+			"}\n",
+	},
+	{
+		"map $uri $redirect {\n" +
+			"\tdefault /;\n" +
+			"\t\"/with space\" /nospace;\n" +
+			"\t~^/old/(.*)$ /new/$1;\n" +
+			"}\n",
+		[]Redirect{
+			{Original: "/with space", Final: "/nospace"},
+			{Original: "~^/old/(.*)$", Final: "/new/$1"},
+		},
+		"map $uri $redirect {\n" +
+			"\tdefault /;\n" +
+			"\t\"/with space\" /nospace;\n" +
+			"\t~^/old/(.*)$ /new/$1;\n" +
+			"}\n",
+	},
+	{
+		"server {\n" +
+			"\tlisten 80;\n" +
+			"\tserver_name\thelp.example.com docs.example.com;\n" +
+			"\treturn 302\thttp://www.example.com/help#top;\n" +
+			"}\n" +
+			"/en /;\n",
+		[]Redirect{
+			{
+				Original: "help.example.com",
+				Final:    "http://www.example.com/help#top",
+				Server:   true,
+				Code:     302,
+				Listen:   []string{"80"},
+			},
+			{
+				Original: "docs.example.com",
+				Final:    "http://www.example.com/help#top",
+				Server:   true,
+				Code:     302,
+				Listen:   []string{"80"},
+			},
+			{Original: "/en", Final: "/"},
+		},
+		"/en /;\n" +
+			"\n" +
+			"server {\n" +
+			"\tlisten 80;\n" +
+			"\tserver_name\thelp.example.com;\n" +
+			"\treturn 302\thttp://www.example.com/help#top;\n" +
+			"}\n" +
+			"\n" +
+			"server {\n" +
+			"\tlisten 80;\n" +
+			"\tserver_name\tdocs.example.com;\n" +
+			"\treturn 302\thttp://www.example.com/help#top;\n" +
+			"}\n",
+	},
+	{
+		"server {\n" +
+			"\tlisten 80;\n" +
+			"\tlisten 443 ssl;\n" +
+			"\tserver_name\tsecure.example.com;\n" +
+			"\treturn 301\thttps://www.example.com/;\n" +
+			"}\n",
+		[]Redirect{
+			{
+				Original: "secure.example.com",
+				Final:    "https://www.example.com/",
+				Server:   true,
+				Code:     301,
+				Listen:   []string{"80", "443 ssl"},
+			},
+		},
+		"server {\n" +
+			"\tlisten 80;\n" +
+			"\tlisten 443 ssl;\n" +
+			"\tserver_name\tsecure.example.com;\n" +
+			"\treturn 301\thttps://www.example.com/;\n" +
+			"}\n",
+	},
+	{"", nil, ""},
+}
+
+func TestParse(t *testing.T) {
+	for _, tt := range parsetests {
+		list, err := Parse(tt.content)
+		if err != nil {
+			t.Errorf("Unexpected error parsing:\n%s\n%s", tt.content, err)
+			continue
+		}
+		if len(list.Redirects) != len(tt.redirects) {
+			t.Errorf("Expected %v, got %v", tt.redirects, list.Redirects)
+			continue
+		}
+		for i, redirect := range list.Redirects {
+			if !reflect.DeepEqual(*redirect, tt.redirects[i]) {
+				t.Errorf("Expected %v, got %v", tt.redirects[i], *redirect)
+			}
+		}
+		if output := list.String(); output != tt.canonical {
+			t.Errorf("Expected:\n%s\nGot:\n%s", tt.canonical, output)
+		}
+		again, err := Parse(list.String())
+		if err != nil || again.String() != tt.canonical {
+			t.Errorf("Canonical output should parse the same:\n%s", tt.canonical)
+		}
+	}
+}
+
+var parseerrortests = []string{
+	"/en /\n",
+	"/en / /extra;\n",
+	"/en \"/;\n",
+	"server {\n\tlisten 80;\n",
+	"server {\n\tserver_name a.example.com;\n\tlocation / {}\n}\n",
+	"server {\n\tserver_name a.example.com;\n\treturn 200 ok;\n}\n",
+	"server {\n\tlisten 80;\n}\n",
+	"map $uri $a {\n\thostnames;\n}\n",
+	"}\n",
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, content := range parseerrortests {
+		if _, err := Parse(content); err == nil {
+			t.Errorf("Parsing should fail:\n%s", content)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package trek
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// Redirect is a redirect from Original, a path or a hostname, to Final.
+// Server redirects are rendered as server blocks named Original listening
+// on Listen, or port 80 if empty, and returning Code, and the rest as map
+// entries. Comments are rendered before it, and Inline after it.
+type Redirect struct {
+	Original string   `json:"original"`
+	Final    string   `json:"final"`
+	Server   bool     `json:"server,omitempty"`
+	Code     int      `json:"code,omitempty"`
+	Listen   []string `json:"listen,omitempty"`
+	Comments []string `json:"-"`
+	Inline   string   `json:"-"`
+}
+
+// NewRedirect returns the redirect from original to final, a permanent
+// server redirect if original is a hostname and final a URL.
+func NewRedirect(original, final string) (*Redirect, error) {
+	if original == "" || final == "" {
+		return nil, fmt.Errorf("You must specify both original and final")
+	}
+	redirect := &Redirect{Original: original, Final: final}
+	if IsHostname(original) && IsURL(final) {
+		redirect.Server = true
+		redirect.Code = 301
+		redirect.Listen = []string{"80"}
+	}
+	return redirect, nil
+}
+
+// String returns r as nginx configuration.
+func (r *Redirect) String() (output string) {
+	output = comments(r.Comments)
+	if r.Server {
+		listen := r.Listen
+		if len(listen) == 0 {
+			listen = []string{"80"}
+		}
+		output += "server {\n"
+		for _, address := range listen {
+			output += fmt.Sprintf("\tlisten %s;\n", address)
+		}
+		return output + fmt.Sprintf(
+			"\tserver_name\t%s;\n"+
+				"\treturn %d\t%s;\n"+
+				"}\n",
+			quote(r.Original),
+			r.Code,
+			quote(r.Final),
+		)
+	}
+	output += fmt.Sprintf("%s %s;", quote(r.Original), quote(r.Final))
+	if r.Inline != "" {
+		output += " #" + r.Inline
+	}
+	return output + "\n"
+}
+
+// comments returns lines as nginx comments.
+func comments(lines []string) (output string) {
+	for _, line := range lines {
+		output += "#" + line + "\n"
+	}
+	return
+}
+
+// quote returns s quoted if nginx would not read it as a single word,
+// escaping quotes and backslashes.
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n;{}\"'") ||
+		strings.HasPrefix(s, "#") {
+		s = strings.Replace(s, "\\", "\\\\", -1)
+		return "\"" + strings.Replace(s, "\"", "\\\"", -1) + "\""
+	}
+	return s
+}
+
+// RedirectList is a list of redirects, as read from a map file and the
+// server blocks trek generates.
+type RedirectList struct {
+	Redirects []*Redirect
+	// Map holds the variables of the map block wrapping the map entries,
+	// if any, and Default its default value.
+	Map     []string
+	Default string
+	// Comments are rendered before the redirects, and Trailing after the
+	// map entries.
+	Comments []string
+	Trailing []string
+}
+
+// Find returns the redirect from original, or nil if there is none.
+func (l *RedirectList) Find(original string) *Redirect {
+	for _, redirect := range l.Redirects {
+		if redirect.Original == original {
+			return redirect
+		}
+	}
+	return nil
+}
+
+// same returns true if l and other hold the same map entries, server
+// redirects and comments, in the same order.
+func (l *RedirectList) same(other *RedirectList) bool {
+	if !sameStrings(l.Map, other.Map) || l.Default != other.Default ||
+		!sameStrings(l.Comments, other.Comments) ||
+		!sameStrings(l.Trailing, other.Trailing) {
+		return false
+	}
+	for _, server := range []bool{false, true} {
+		redirects, others := l.filter(server), other.filter(server)
+		if len(redirects) != len(others) {
+			return false
+		}
+		for i, redirect := range redirects {
+			if !redirect.same(others[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// filter returns the server redirects in l, or the map entries.
+func (l *RedirectList) filter(server bool) (redirects []*Redirect) {
+	for _, redirect := range l.Redirects {
+		if redirect.Server == server {
+			redirects = append(redirects, redirect)
+		}
+	}
+	return
+}
+
+// same returns true if r and other are the same redirect, with the same
+// comments.
+func (r *Redirect) same(other *Redirect) bool {
+	return r.Original == other.Original && r.Final == other.Final &&
+		r.Server == other.Server && r.Code == other.Code &&
+		r.Inline == other.Inline &&
+		sameStrings(r.Listen, other.Listen) &&
+		sameStrings(r.Comments, other.Comments)
+}
+
+// sameStrings returns true if a and b hold the same strings in order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Duplicates returns the redirects from an original some previous
+// redirect in l is from already.
+func (l *RedirectList) Duplicates() (duplicates []*Redirect) {
+	seen := map[string]bool{}
+	for _, redirect := range l.Redirects {
+		if seen[redirect.Original] {
+			duplicates = append(duplicates, redirect)
+		}
+		seen[redirect.Original] = true
+	}
+	return
+}
+
+// Set redirects original to final, updating the existing redirect from
+// original, and dropping its duplicates, or adding a new one.
+func (l *RedirectList) Set(original, final string) error {
+	redirect, err := NewRedirect(original, final)
+	if err != nil {
+		return err
+	}
+	existing := l.Find(original)
+	if existing == nil {
+		l.Redirects = append(l.Redirects, redirect)
+		return nil
+	}
+	existing.Final = final
+	redirects := []*Redirect{}
+	for _, redirect := range l.Redirects {
+		if redirect == existing || redirect.Original != original {
+			redirects = append(redirects, redirect)
+		}
+	}
+	l.Redirects = redirects
+	return nil
+}
+
+// Remove drops all the redirects from original.
+func (l *RedirectList) Remove(original string) error {
+	if l.Find(original) == nil {
+		return fmt.Errorf("No redirect from %s", original)
+	}
+	redirects := []*Redirect{}
+	for _, redirect := range l.Redirects {
+		if redirect.Original != original {
+			redirects = append(redirects, redirect)
+		}
+	}
+	l.Redirects = redirects
+	return nil
+}
+
+// Table returns the redirects in l as a table, with the code of server
+// redirects.
+func (l *RedirectList) Table() string {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "ORIGINAL\tFINAL\tCODE")
+	for _, redirect := range l.Redirects {
+		code := "-"
+		if redirect.Server {
+			code = fmt.Sprint(redirect.Code)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", redirect.Original, redirect.Final, code)
+	}
+	w.Flush()
+	return b.String()
+}
+
+// JSON returns the redirects in l in JSON format.
+func (l *RedirectList) JSON() (string, error) {
+	redirects := l.Redirects
+	if redirects == nil {
+		redirects = []*Redirect{}
+	}
+	out, err := json.MarshalIndent(redirects, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+// String renders l canonically: map entries first, in their map block if
+// read from one, followed by server blocks. Trailing comments are rendered
+// at the end of the map block, or of the output if there is none.
+func (l *RedirectList) String() (output string) {
+	entries := ""
+	for _, redirect := range l.Redirects {
+		if !redirect.Server {
+			entries += redirect.String()
+		}
+	}
+	output = comments(l.Comments)
+	if len(l.Map) > 0 {
+		entries += comments(l.Trailing)
+		output += fmt.Sprintf("map %s {\n", strings.Join(l.Map, " "))
+		if l.Default != "" {
+			output += fmt.Sprintf("\tdefault %s;\n", quote(l.Default))
+		}
+		for _, line := range strings.SplitAfter(entries, "\n") {
+			if line != "" {
+				output += "\t" + line
+			}
+		}
+		output += "}\n"
+	} else {
+		output += entries
+	}
+	for _, redirect := range l.Redirects {
+		if redirect.Server {
+			if output != "" {
+				output += "\n"
+			}
+			output += redirect.String()
+		}
+	}
+	if len(l.Map) == 0 {
+		output += comments(l.Trailing)
+	}
+	return
+}
@@ -0,0 +1,92 @@
+package trek
+
+import (
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	list, err := Parse("/a /b;\n/c /d;\n/a /e;\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	duplicates := list.Duplicates()
+	if len(duplicates) != 1 || duplicates[0].Final != "/e" {
+		t.Errorf("Expected /a /e to be a duplicate, got %v", duplicates)
+	}
+	if err := list.Set("/a", "/f"); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Set("/g", "/h"); err != nil {
+		t.Fatal(err)
+	}
+	expected := "/a /f;\n/c /d;\n/g /h;\n"
+	if output := list.String(); output != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, output)
+	}
+	if err := list.Set("", "/h"); err == nil {
+		t.Error("Original lacking call should fail")
+	}
+}
+
+func TestRemoveRedirect(t *testing.T) {
+	list, err := Parse("/a /b;\n/c /d;\n/a /e;\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Remove("/a"); err != nil {
+		t.Fatal(err)
+	}
+	expected := "/c /d;\n"
+	if output := list.String(); output != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, output)
+	}
+	if err := list.Remove("/a"); err == nil {
+		t.Error("Removing a missing redirect should fail")
+	}
+}
+
+func TestTable(t *testing.T) {
+	list, err := Parse(
+		"/en /;\n" +
+			"server {\n" +
+			"\tserver_name help.example.com;\n" +
+			"\treturn 302 http://www.example.com/help;\n" +
+			"}\n",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "ORIGINAL          FINAL                        CODE\n" +
+		"/en               /                            -\n" +
+		"help.example.com  http://www.example.com/help  302\n"
+	if output := list.Table(); output != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, output)
+	}
+	output, err := list.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `[
+  {
+    "original": "/en",
+    "final": "/"
+  },
+  {
+    "original": "help.example.com",
+    "final": "http://www.example.com/help",
+    "server": true,
+    "code": 302,
+    "listen": [
+      "80"
+    ]
+  }
+]
+`
+	if output != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, output)
+	}
+	empty := &RedirectList{}
+	if output, _ := empty.JSON(); output != "[]\n" {
+		t.Errorf("Expected an empty list, got %s", output)
+	}
+}
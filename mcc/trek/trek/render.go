@@ -0,0 +1,356 @@
+package trek
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MapCode is the status code of redirects in map entries, as nginx
+// servers return them.
+const MapCode = 301
+
+// Renderer renders a redirect list as the configuration of a server.
+type Renderer interface {
+	Render(l *RedirectList) (string, error)
+}
+
+// Renderers are the available renderers by name.
+var Renderers = map[string]Renderer{
+	"nginx":   Nginx{},
+	"apache":  Apache{},
+	"haproxy": HAProxy{MapFile: "/etc/haproxy/redirects.map"},
+	"s3":      S3{},
+}
+
+// RendererNames returns the names of the available renderers, sorted.
+func RendererNames() (names []string) {
+	for name := range Renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// pattern returns the regular expression of a map entry original, which
+// is a regular expression itself if it starts with ~, or ~* when case
+// insensitive, and an exact match otherwise.
+func pattern(original string) (expression string, insensitive, regex bool) {
+	switch {
+	case strings.HasPrefix(original, "~*"):
+		return original[2:], true, true
+	case strings.HasPrefix(original, "~"):
+		return original[1:], false, true
+	}
+	return "^" + regexp.QuoteMeta(original) + "$", false, false
+}
+
+// variables matches nginx variables, as $name or ${name}, captures being
+// numbered.
+var variables = regexp.MustCompile(`\$(\w+|\{\w+\})`)
+
+// translate returns the final of redirect with the nginx variables in it
+// replaced by their equivalents in server, and captures as $1 if server
+// supports them, or an error if there's any other variable.
+func translate(
+	redirect *Redirect,
+	server string,
+	equivalents map[string]string,
+	captures bool,
+) (final string, err error) {
+	final = variables.ReplaceAllStringFunc(
+		redirect.Final,
+		func(variable string) string {
+			name := strings.Trim(variable, "${}")
+			if equivalent, ok := equivalents[name]; ok {
+				return equivalent
+			}
+			if _, numberErr := strconv.Atoi(name); numberErr == nil && captures {
+				return "$" + name
+			}
+			if err == nil {
+				err = fmt.Errorf(
+					"%s can't redirect %s to %s, with %s",
+					server,
+					redirect.Original,
+					redirect.Final,
+					variable,
+				)
+			}
+			return variable
+		},
+	)
+	return
+}
+
+// ports returns the ports of nginx listen directives, 80 for those only
+// giving an address.
+func ports(listen []string) (ports []string) {
+	seen := map[string]bool{}
+	for _, directive := range listen {
+		address := strings.Fields(directive)[0]
+		port := address[strings.LastIndex(address, ":")+1:]
+		if _, err := strconv.Atoi(port); err != nil {
+			port = "80"
+		}
+		if !seen[port] {
+			ports = append(ports, port)
+		}
+		seen[port] = true
+	}
+	if len(ports) == 0 {
+		ports = []string{"80"}
+	}
+	return
+}
+
+// Nginx renders redirects as a map file and server blocks, the format
+// trek reads.
+type Nginx struct{}
+
+// Render returns l canonically.
+func (n Nginx) Render(l *RedirectList) (string, error) {
+	return l.String(), nil
+}
+
+// Apache renders redirects as rewrite rules, and virtual hosts on the
+// ports servers listen on for redirects from hostnames. Redirect
+// directives aren't used, as they append the rest of the path to the final
+// URL, where nginx doesn't.
+type Apache struct{}
+
+// apacheVariables are the Apache equivalents of nginx variables.
+var apacheVariables = map[string]string{
+	"request_uri":  "%{REQUEST_URI}",
+	"scheme":       "%{REQUEST_SCHEME}",
+	"host":         "%{HTTP_HOST}",
+	"args":         "%{QUERY_STRING}",
+	"query_string": "%{QUERY_STRING}",
+}
+
+// Render returns l as Apache configuration.
+func (a Apache) Render(l *RedirectList) (string, error) {
+	rules, hosts := "", ""
+	for _, redirect := range l.Redirects {
+		final, err := translate(
+			redirect,
+			"Apache",
+			apacheVariables,
+			!redirect.Server,
+		)
+		if err != nil {
+			return "", err
+		}
+		if redirect.Server {
+			addresses := []string{}
+			for _, port := range ports(redirect.Listen) {
+				addresses = append(addresses, "*:"+port)
+			}
+			hosts += fmt.Sprintf(
+				"\n<VirtualHost %s>\n"+
+					"\tServerName %s\n"+
+					"\tRewriteEngine On\n"+
+					"\tRewriteRule ^ %s [R=%d,L]\n"+
+					"</VirtualHost>\n",
+				strings.Join(addresses, " "),
+				redirect.Original,
+				apacheQuote(final),
+				redirect.Code,
+			)
+			continue
+		}
+		expression, insensitive, _ := pattern(redirect.Original)
+		flags := fmt.Sprintf("R=%d,L", MapCode)
+		if insensitive {
+			flags += ",NC"
+		}
+		rules += fmt.Sprintf(
+			"RewriteRule %s %s [%s]\n",
+			apacheQuote(expression),
+			apacheQuote(final),
+			flags,
+		)
+	}
+	if rules != "" {
+		rules = "RewriteEngine On\n" + rules
+	}
+	return strings.TrimPrefix(rules+hosts, "\n"), nil
+}
+
+// apacheQuote returns s quoted if Apache would not read it as a single
+// argument.
+func apacheQuote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\"") {
+		return "\"" + strings.Replace(s, "\"", "\\\"", -1) + "\""
+	}
+	return s
+}
+
+// HAProxy renders redirects as a map file, with the entries matching
+// paths exactly, followed by the http-request redirect rules for a
+// frontend, using the map file at MapFile. As in nginx, the map file is
+// looked up before the regular expressions. Redirects from hostnames only
+// apply to the ports their servers listen on.
+type HAProxy struct {
+	MapFile string
+}
+
+// haproxyVariables are the HAProxy equivalents of nginx variables.
+var haproxyVariables = map[string]string{
+	"request_uri":  "%[url]",
+	"uri":          "%[path]",
+	"host":         "%[req.hdr(host)]",
+	"args":         "%[query]",
+	"query_string": "%[query]",
+}
+
+// Render returns l as an HAProxy map file and frontend rules.
+func (h HAProxy) Render(l *RedirectList) (string, error) {
+	entries, hosts, expressions := "", "", ""
+	for _, redirect := range l.Redirects {
+		if strings.ContainsAny(redirect.Original+redirect.Final, " \t") {
+			return "", fmt.Errorf(
+				"HAProxy can't redirect %s to %s, with spaces",
+				redirect.Original,
+				redirect.Final,
+			)
+		}
+		expression, insensitive, regex := pattern(redirect.Original)
+		equivalents := haproxyVariables
+		if !redirect.Server && !regex {
+			// Map file values aren't expanded
+			equivalents = nil
+		}
+		final, err := translate(redirect, "HAProxy", equivalents, false)
+		if err != nil {
+			return "", err
+		}
+		if redirect.Server {
+			hosts += fmt.Sprintf(
+				"http-request redirect code %d location %s "+
+					"if { hdr(host) -i %s } { dst_port %s }\n",
+				redirect.Code,
+				final,
+				redirect.Original,
+				strings.Join(ports(redirect.Listen), " "),
+			)
+			continue
+		}
+		if !regex {
+			entries += fmt.Sprintf("%s %s\n", redirect.Original, final)
+			continue
+		}
+		flag := ""
+		if insensitive {
+			flag = "-i "
+		}
+		expressions += fmt.Sprintf(
+			"http-request redirect code %d location %s if { path_reg %s%s }\n",
+			MapCode,
+			final,
+			flag,
+			expression,
+		)
+	}
+	rules := hosts
+	if entries != "" {
+		rules += fmt.Sprintf(
+			"http-request redirect code %d location %%[path,map(%s)] "+
+				"if { path,map(%s) -m found }\n",
+			MapCode,
+			h.MapFile,
+			h.MapFile,
+		)
+	}
+	rules += expressions
+	return fmt.Sprintf("# %s\n%s\n# frontend\n%s", h.MapFile, entries, rules), nil
+}
+
+// S3 renders redirects as the routing rules of an S3 website, in JSON
+// format. S3 only matches key prefixes, and applies the first rule
+// matching, so rules are sorted from the longest prefix to the shortest,
+// and a warning is logged for those matching other redirects' originals.
+type S3 struct{}
+
+// s3Condition is the condition of an S3 routing rule.
+type s3Condition struct {
+	KeyPrefixEquals string `json:"KeyPrefixEquals"`
+}
+
+// s3Redirect is the redirect of an S3 routing rule.
+type s3Redirect struct {
+	Protocol         string `json:"Protocol,omitempty"`
+	HostName         string `json:"HostName,omitempty"`
+	ReplaceKeyWith   string `json:"ReplaceKeyWith"`
+	HTTPRedirectCode string `json:"HttpRedirectCode"`
+}
+
+// s3RoutingRule is an S3 routing rule.
+type s3RoutingRule struct {
+	Condition s3Condition `json:"Condition"`
+	Redirect  s3Redirect  `json:"Redirect"`
+}
+
+// s3URL matches the URLs S3 can redirect to.
+var s3URL = regexp.MustCompile(`^(https?)://([^/]+)(/.*)?$`)
+
+// Render returns l as S3 website routing rules.
+func (s S3) Render(l *RedirectList) (string, error) {
+	rules := []s3RoutingRule{}
+	for _, redirect := range l.Redirects {
+		if _, _, regex := pattern(redirect.Original); regex || redirect.Server {
+			return "", fmt.Errorf(
+				"S3 can't redirect %s, only paths",
+				redirect.Original,
+			)
+		}
+		if _, err := translate(redirect, "S3", nil, false); err != nil {
+			return "", err
+		}
+		rule := s3RoutingRule{
+			Condition: s3Condition{
+				KeyPrefixEquals: strings.TrimPrefix(redirect.Original, "/"),
+			},
+			Redirect: s3Redirect{HTTPRedirectCode: fmt.Sprint(MapCode)},
+		}
+		key := redirect.Final
+		if match := s3URL.FindStringSubmatch(redirect.Final); match != nil {
+			rule.Redirect.Protocol = match[1]
+			rule.Redirect.HostName = match[2]
+			key = match[3]
+		}
+		rule.Redirect.ReplaceKeyWith = strings.TrimPrefix(key, "/")
+		if rule.Condition.KeyPrefixEquals == "" {
+			return "", fmt.Errorf(
+				"S3 can't redirect %s, as it would match every key",
+				redirect.Original,
+			)
+		}
+		rules = append(rules, rule)
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return len(rules[i].Condition.KeyPrefixEquals) >
+			len(rules[j].Condition.KeyPrefixEquals)
+	})
+	for i, rule := range rules {
+		for _, shorter := range rules[i+1:] {
+			prefix := shorter.Condition.KeyPrefixEquals
+			if strings.HasPrefix(rule.Condition.KeyPrefixEquals, prefix) {
+				log.Printf(
+					"S3 rule for %s covers %s, whose rule is applied first",
+					prefix,
+					rule.Condition.KeyPrefixEquals,
+				)
+			}
+		}
+	}
+	out, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
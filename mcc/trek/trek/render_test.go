@@ -0,0 +1,134 @@
+package trek
+
+import (
+	"testing"
+)
+
+// redirects holds paths, regular expressions and hostnames redirects.
+const redirects = "/en /;\n" +
+	"/en/about http://www.example.com/about;\n" +
+	"~*^/blog/.*$ /news;\n" +
+	"server {\n" +
+	"\tserver_name help.example.com;\n" +
+	"\treturn 302 http://www.example.com/help;\n" +
+	"}\n"
+
+// secure holds an empty final, variables, and a server listening on
+// several ports.
+const secure = "/a \"\";\n" +
+	"~^/old/(.*)$ /new/$1?host=$host;\n" +
+	"server {\n" +
+	"\tlisten 80;\n" +
+	"\tlisten 443 ssl;\n" +
+	"\tserver_name secure.example.com;\n" +
+	"\treturn 301 https://www.example.com$request_uri;\n" +
+	"}\n"
+
+var rendertests = []struct {
+	renderer Renderer
+	content  string
+	output   string
+	fail     bool
+}{
+	{Nginx{}, redirects, "/en /;\n" +
+		"/en/about http://www.example.com/about;\n" +
+		"~*^/blog/.*$ /news;\n" +
+		"\n" +
+		"server {\n" +
+		"\tlisten 80;\n" +
+		"\tserver_name\thelp.example.com;\n" +
+		"\treturn 302\thttp://www.example.com/help;\n" +
+		"}\n", false},
+	{Apache{}, redirects, "RewriteEngine On\n" +
+		"RewriteRule ^/en$ / [R=301,L]\n" +
+		"RewriteRule ^/en/about$ http://www.example.com/about [R=301,L]\n" +
+		"RewriteRule ^/blog/.*$ /news [R=301,L,NC]\n" +
+		"\n" +
+		"<VirtualHost *:80>\n" +
+		"\tServerName help.example.com\n" +
+		"\tRewriteEngine On\n" +
+		"\tRewriteRule ^ http://www.example.com/help [R=302,L]\n" +
+		"</VirtualHost>\n", false},
+	{HAProxy{MapFile: "redirects.map"}, redirects, "# redirects.map\n" +
+		"/en /\n" +
+		"/en/about http://www.example.com/about\n" +
+		"\n" +
+		"# frontend\n" +
+		"http-request redirect code 302 location http://www.example.com/help if { hdr(host) -i help.example.com } { dst_port 80 }\n" +
+		"http-request redirect code 301 location %[path,map(redirects.map)] if { path,map(redirects.map) -m found }\n" +
+		"http-request redirect code 301 location /news if { path_reg -i ^/blog/.*$ }\n", false},
+	{Apache{}, secure, "RewriteEngine On\n" +
+		"RewriteRule ^/a$ \"\" [R=301,L]\n" +
+		"RewriteRule ^/old/(.*)$ /new/$1?host=%{HTTP_HOST} [R=301,L]\n" +
+		"\n" +
+		"<VirtualHost *:80 *:443>\n" +
+		"\tServerName secure.example.com\n" +
+		"\tRewriteEngine On\n" +
+		"\tRewriteRule ^ https://www.example.com%{REQUEST_URI} [R=301,L]\n" +
+		"</VirtualHost>\n", false},
+	{Apache{}, "/a /b?$cookie_id;\n", "", true},
+	{HAProxy{MapFile: "redirects.map"}, "~^/old/.*$ /new?${args};\n" +
+		"server {\n" +
+		"\tlisten [::]:8080;\n" +
+		"\tserver_name secure.example.com;\n" +
+		"\treturn 301 https://www.example.com$request_uri;\n" +
+		"}\n", "# redirects.map\n" +
+		"\n" +
+		"# frontend\n" +
+		"http-request redirect code 301 location https://www.example.com%[url] if { hdr(host) -i secure.example.com } { dst_port 8080 }\n" +
+		"http-request redirect code 301 location /new?%[query] if { path_reg ^/old/.*$ }\n", false},
+	{HAProxy{MapFile: "redirects.map"}, "~^/old/(.*)$ /new/$1;\n", "", true},
+	{HAProxy{MapFile: "redirects.map"}, "/a $scheme://www.example.com/;\n", "", true},
+	{HAProxy{MapFile: "redirects.map"}, "/a /b?$args;\n", "", true},
+	{HAProxy{MapFile: "redirects.map"}, "\"/a b\" /c;\n", "", true},
+	{S3{}, "/en /;\n/en/about http://www.example.com/about;\n", `[
+  {
+    "Condition": {
+      "KeyPrefixEquals": "en/about"
+    },
+    "Redirect": {
+      "Protocol": "http",
+      "HostName": "www.example.com",
+      "ReplaceKeyWith": "about",
+      "HttpRedirectCode": "301"
+    }
+  },
+  {
+    "Condition": {
+      "KeyPrefixEquals": "en"
+    },
+    "Redirect": {
+      "ReplaceKeyWith": "",
+      "HttpRedirectCode": "301"
+    }
+  }
+]
+`, false},
+	{S3{}, redirects, "", true},
+	{S3{}, "/ /home;\n", "", true},
+	{S3{}, "/a /b$request_uri;\n", "", true},
+}
+
+func TestRender(t *testing.T) {
+	for _, tt := range rendertests {
+		output, err := Render(tt.content, tt.renderer)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("Rendering with %T should fail:\n%s", tt.renderer, tt.content)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error rendering with %T: %s", tt.renderer, err)
+			continue
+		}
+		if output != tt.output {
+			t.Errorf(
+				"Rendering with %T expected:\n%s\nGot:\n%s",
+				tt.renderer,
+				tt.output,
+				output,
+			)
+		}
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"regexp"
 )
 
@@ -64,28 +66,116 @@ func ReadFromPipe() (string, error) {
 	return out, nil
 }
 
-// Add adds a new redirect line for original to final, and returns it.
-func Add(initialContent, original, final string) (
+// edit applies change to the redirects in initialContent, and returns
+// them rendered with renderer. It fails if the changed redirects wouldn't
+// be read back the same from their nginx configuration, so editing files
+// in place loses nothing else.
+func edit(
+	initialContent string,
+	renderer Renderer,
+	change func(list *RedirectList) error,
+) (result string, err error) {
+	list, err := Parse(initialContent)
+	if err != nil {
+		return
+	}
+	if err = change(list); err != nil {
+		return
+	}
+	again, err := Parse(list.String())
+	if err != nil || !list.same(again) {
+		return "", fmt.Errorf("Redirects would not be read back as they are")
+	}
+	return renderer.Render(list)
+}
+
+// Render returns the redirects in initialContent rendered with renderer.
+func Render(initialContent string, renderer Renderer) (string, error) {
+	return edit(initialContent, renderer, func(list *RedirectList) error {
+		return nil
+	})
+}
+
+// Add redirects original to final in the redirects in initialContent,
+// updating the existing redirect from original if any, and returns them
+// rendered with renderer.
+func Add(initialContent, original, final string, renderer Renderer) (
 	result string, err error,
 ) {
-	if original == "" || final == "" {
-		err = fmt.Errorf("You must specify both original and final")
+	return edit(initialContent, renderer, func(list *RedirectList) error {
+		return list.Set(original, final)
+	})
+}
+
+// Update redirects original, which must be redirected already, to final in
+// the redirects in initialContent, and returns them rendered with renderer.
+func Update(initialContent, original, final string, renderer Renderer) (
+	result string, err error,
+) {
+	return edit(initialContent, renderer, func(list *RedirectList) error {
+		if list.Find(original) == nil {
+			return fmt.Errorf("No redirect from %s", original)
+		}
+		return list.Set(original, final)
+	})
+}
+
+// Remove removes the redirects from original in the redirects in
+// initialContent, and returns them rendered with renderer.
+func Remove(initialContent, original string, renderer Renderer) (
+	result string, err error,
+) {
+	return edit(initialContent, renderer, func(list *RedirectList) error {
+		return list.Remove(original)
+	})
+}
+
+// Flatten points the chains in the redirects in initialContent straight
+// to their final destination, and returns them rendered with renderer,
+// the issues fixed, and those left.
+func Flatten(initialContent string, hosts []string, renderer Renderer) (
+	result string, flattened, issues []*Issue, err error,
+) {
+	result, err = edit(initialContent, renderer, func(list *RedirectList) error {
+		flattened = list.Flatten(hosts)
+		issues = list.Check(hosts)
+		return nil
+	})
+	return
+}
+
+// WriteFile replaces the contents of the file at path with content,
+// keeping the previous ones in a backup file next to it, whose path it
+// returns. Content is written to a temporary file first, and renamed to
+// path, so the file is never left half written.
+func WriteFile(path, content string) (backup string, err error) {
+	info, err := os.Stat(path)
+	if err != nil {
 		return
 	}
-	newContent := ""
-	if IsHostname(original) && IsURL(final) {
-		newContent = fmt.Sprintf(
-			"\nserver {\n"+
-				"\tlisten 80;\n"+
-				"\tserver_name\t%s;\n"+
-				"\treturn 301\t%s;\n"+
-				"}\n",
-			original,
-			final,
-		)
-	} else {
-		newContent = fmt.Sprintf("%s %s;\n", original, final)
+	previous, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+	backup = path + ".bak"
+	if err = ioutil.WriteFile(backup, previous, info.Mode()); err != nil {
+		return
+	}
+	temp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return
+	}
+	defer os.Remove(temp.Name())
+	_, err = temp.WriteString(content)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return
+	}
+	if err = os.Chmod(temp.Name(), info.Mode()); err != nil {
+		return
 	}
-	result = fmt.Sprintf("%s%s", initialContent, newContent)
+	err = os.Rename(temp.Name(), path)
 	return
 }
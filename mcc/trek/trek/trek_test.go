@@ -2,6 +2,9 @@ package trek
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -49,19 +52,19 @@ func TestIsHostname(t *testing.T) {
 func TestAdd(t *testing.T) {
 	redirect := "/en /;\n"
 	expected := fmt.Sprintf("%s/en/about /about;\n", redirect)
-	output, err := Add(redirect, "/en/about", "/about")
+	output, err := Add(redirect, "/en/about", "/about", Nginx{})
 	if output != expected && err == nil {
 		t.Errorf("Redirect for /en/about was not added:\n%s", output)
 	}
-	_, err = Add(redirect, "", "/")
+	_, err = Add(redirect, "", "/", Nginx{})
 	if err == nil {
 		t.Errorf("Original lacking call should fail")
 	}
-	_, err = Add(redirect, "/en/about", "")
+	_, err = Add(redirect, "/en/about", "", Nginx{})
 	if err == nil {
 		t.Errorf("Final lacking call should fail")
 	}
-	_, err = Add(redirect, "", "")
+	_, err = Add(redirect, "", "", Nginx{})
 	if err == nil {
 		t.Errorf("Both original and final should be present")
 	}
@@ -78,7 +81,7 @@ func TestAdd(t *testing.T) {
 		"\treturn 301\thttp://www.example.com/about;\n" +
 		"}"
 	expected = fmt.Sprintf("%s\n%s\n", vhosts, vhostToAdd)
-	output, err = Add(vhosts, hostname, url)
+	output, err = Add(vhosts, hostname, url, Nginx{})
 	if output != expected && err == nil {
 		t.Errorf(
 			"VHost to %s was not added: \n%s\n++++++\n%s",
@@ -88,3 +91,93 @@ func TestAdd(t *testing.T) {
 		)
 	}
 }
+
+// TestUpdate tests updating an existing redirect.
+func TestUpdate(t *testing.T) {
+	redirects := "/en /;\n/en/about /about;\n"
+	expected := "/en /;\n/en/about /about-us;\n"
+	output, err := Update(redirects, "/en/about", "/about-us", Nginx{})
+	if err != nil || output != expected {
+		t.Errorf("Redirect for /en/about was not updated:\n%s", output)
+	}
+	_, err = Update(redirects, "/es", "/", Nginx{})
+	if err == nil {
+		t.Errorf("Updating a missing redirect should fail")
+	}
+}
+
+// TestRemove tests removing a redirect.
+func TestRemove(t *testing.T) {
+	redirects := "/en /;\n/en/about /about;\n"
+	expected := "/en /;\n"
+	output, err := Remove(redirects, "/en/about", Nginx{})
+	if err != nil || output != expected {
+		t.Errorf("Redirect for /en/about was not removed:\n%s", output)
+	}
+	_, err = Remove(redirects, "/es", Nginx{})
+	if err == nil {
+		t.Errorf("Removing a missing redirect should fail")
+	}
+}
+
+// TestEditReadBack tests refusing changes which wouldn't be read back the
+// same.
+func TestEditReadBack(t *testing.T) {
+	// A default entry outside the map block is read back as its default
+	redirects := "map $uri $redirect {\n\t/en /;\n}\ndefault /es;\n"
+	if output, err := Remove(redirects, "/en", Nginx{}); err == nil {
+		t.Errorf("Removing should fail, as the result reads back differently:\n%s", output)
+	}
+	redirects = "# Redirects\n/en /; # English\n\"~^/a/(\\d+)$\" /a;\n"
+	expected := "# Redirects\n/en /; # English\n~^/a/(\\d+)$ /a;\n/es /;\n"
+	output, err := Add(redirects, "/es", "/", Nginx{})
+	if err != nil || output != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s\n%v", expected, output, err)
+	}
+}
+
+// TestFlattenRedirects tests flattening the chains in redirects.
+func TestFlattenRedirects(t *testing.T) {
+	redirects := "/a /b;\n/b /c;\n/x /y;\n/y /x;\n"
+	expected := "/a /c;\n/b /c;\n/x /y;\n/y /x;\n"
+	output, flattened, issues, err := Flatten(redirects, nil, Nginx{})
+	if err != nil || output != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s\n%v", expected, output, err)
+	}
+	if len(flattened) != 1 || len(issues) != 2 {
+		t.Errorf("Expected 1 chain flattened and 2 loops left, got %v and %v", flattened, issues)
+	}
+}
+
+// TestWriteFile tests replacing a file keeping a backup.
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trek")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "redirects.map")
+	if err := ioutil.WriteFile(path, []byte("/en /;\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := WriteFile(path, "/es /;\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for file, expected := range map[string]string{
+		path:   "/es /;\n",
+		backup: "/en /;\n",
+	} {
+		content, err := ioutil.ReadFile(file)
+		if err != nil || string(content) != expected {
+			t.Errorf("Expected %s to contain %q, got %q", file, expected, content)
+		}
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil || len(files) != 2 {
+		t.Errorf("Expected only the file and its backup, got %v", files)
+	}
+	if _, err := WriteFile(filepath.Join(dir, "missing"), ""); err == nil {
+		t.Errorf("Writing a missing file should fail")
+	}
+}